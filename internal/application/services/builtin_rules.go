@@ -0,0 +1,296 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/validation"
+)
+
+var (
+	emailRegex  = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	letterRegex = regexp.MustCompile(`[a-zA-Z]`)
+)
+
+// characterClasses relaciona cada clase de caracteres de un nombre con su descripción
+// y la función que la reconoce
+var characterClasses = map[string]struct {
+	label   string
+	matches func(rune) bool
+}{
+	entities.CharacterClassLetters:     {"letras", unicode.IsLetter},
+	entities.CharacterClassSpaces:      {"espacios", unicode.IsSpace},
+	entities.CharacterClassApostrophes: {"apostrofes", func(char rune) bool { return char == '\'' }},
+	entities.CharacterClassPeriods:     {"puntos", func(char rune) bool { return char == '.' }},
+	entities.CharacterClassHyphens:     {"guiones", func(char rune) bool { return char == '-' }},
+	entities.CharacterClassDigits:      {"dígitos", unicode.IsDigit},
+}
+
+// allowsNameCharacter indica si alguna de las clases de caracteres acepta el carácter
+func allowsNameCharacter(classes []string, char rune) bool {
+	for _, class := range classes {
+		if characterClass, exists := characterClasses[class]; exists && characterClass.matches(char) {
+			return true
+		}
+	}
+	return false
+}
+
+// profileRules crea las reglas de validación incluidas, configuradas según el perfil. Las
+// reglas que dependen de listas que pueden estar incompletas (dominios conocidos, números
+// que parecen ficticios) solo generan advertencias.
+func profileRules(profile entities.ValidationProfile) ([]validation.Rule, error) {
+	clientKeyFormat, err := clientKeyFormatRule(profile.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	nameCharacters, err := nameCharactersRule(profile.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedDomains := lowerAll(profile.Email.AllowedDomains)
+	blockedDomains := lowerAll(profile.Email.BlockedDomains)
+	areaCodes := profile.Phone.AreaCodes
+	states := lowerAll(profile.Phone.States)
+	countries := upperAll(profile.Phone.Countries)
+	phoneLength := profile.Phone.Length
+
+	return []validation.Rule{
+		// Clave cliente
+		NewRule("client_key.required", entities.FieldClientKey, "REQUIRED", entities.SeverityError,
+			"La clave cliente no puede estar vacía", notEmpty),
+		clientKeyFormat,
+		clientKeyLengthRule(profile.ClientKey),
+
+		// Nombre
+		NewRule("name.required", entities.FieldName, "REQUIRED", entities.SeverityError,
+			"El nombre no puede estar vacío", notEmpty),
+		nameCharacters,
+
+		// Email: formato básico y dominio
+		NewRule("email.required", entities.FieldEmail, "REQUIRED", entities.SeverityError,
+			"El email no puede estar vacío", notEmpty),
+		NewRule("email.format", entities.FieldEmail, "INVALID_FORMAT", entities.SeverityError,
+			"El formato del email no es válido", func(value string) bool {
+				return emailRegex.MatchString(value) && strings.Count(value, "@") == 1
+			}),
+		NewRule("email.domain", entities.FieldEmail, "INVALID_DOMAIN", entities.SeverityWarning,
+			fmt.Sprintf("El dominio del email no es reconocido (use %s, etc.)", strings.Join(allowedDomains[:min(3, len(allowedDomains))], ", ")),
+			func(value string) bool {
+				return len(allowedDomains) == 0 || containsString(allowedDomains, emailDomain(value))
+			}),
+		NewRule("email.blocked_domain", entities.FieldEmail, "BLOCKED_DOMAIN", entities.SeverityError,
+			"El dominio del email no está permitido", func(value string) bool {
+				return !containsString(blockedDomains, emailDomain(value))
+			}),
+
+		// Teléfono: país, longitud, lada del plan de numeración y restricción regional.
+		// Las reglas revisan el número normalizado, sin prefijos de marcación ni extensión.
+		NewRule("phone.required", entities.FieldPhone, "REQUIRED", entities.SeverityError,
+			"El teléfono no puede estar vacío", notEmpty),
+		NewRule("phone.no_letters", entities.FieldPhone, "INVALID_CHARACTER", entities.SeverityError,
+			"El teléfono no debe contener letras", func(value string) bool {
+				return !letterRegex.MatchString(extensionRegex.ReplaceAllString(value, ""))
+			}),
+		newPhoneRule("phone.country_code", "UNKNOWN_COUNTRY_CODE", entities.SeverityError,
+			"El código de país del teléfono no es reconocido", func(parsed parsedPhone) bool {
+				return parsed.Country != ""
+			}),
+		newPhoneRule("phone.country", "INVALID_COUNTRY", entities.SeverityError,
+			fmt.Sprintf("El teléfono debe ser de %s", strings.Join(countries, ", ")), func(parsed parsedPhone) bool {
+				return len(countries) == 0 || containsString(countries, parsed.Country)
+			}),
+		newPhoneRule("phone.length", "INVALID_LENGTH", entities.SeverityError,
+			fmt.Sprintf("El teléfono debe tener exactamente %d dígitos", phoneLength), func(parsed parsedPhone) bool {
+				return parsed.Country != defaultPhoneCountry || len(parsed.National) == phoneLength
+			}),
+		newPhoneRule("phone.country_length", "INVALID_LENGTH", entities.SeverityError,
+			"El teléfono no tiene una longitud válida para su país", func(parsed parsedPhone) bool {
+				return parsed.Country == defaultPhoneCountry || parsed.ValidLength()
+			}),
+		newPhoneRule("phone.numbering_plan", "UNKNOWN_AREA_CODE", entities.SeverityWarning,
+			"La lada no existe en el plan de numeración nacional", func(parsed parsedPhone) bool {
+				national, ok := mexicanNumber(parsed)
+				if !ok {
+					return true
+				}
+				_, exists := lookupAreaCode(national)
+				return exists
+			}),
+		newPhoneRule("phone.real_number", "INVALID_NUMBER", entities.SeverityWarning,
+			"El número local no es válido (no puede ser un dígito repetido o una secuencia)", func(parsed parsedPhone) bool {
+				national, ok := mexicanNumber(parsed)
+				if !ok {
+					return true
+				}
+				location, exists := lookupAreaCode(national)
+				return !exists || isRealSubscriber(national, location.AreaCode)
+			}),
+		newPhoneRule("phone.area_code", "INVALID_AREA_CODE", entities.SeverityError,
+			areaCodeMessage(profile.Phone), func(parsed parsedPhone) bool {
+				if len(areaCodes) == 0 {
+					return true
+				}
+				national, ok := mexicanNumber(parsed)
+				if !ok {
+					return false
+				}
+				for _, code := range areaCodes {
+					if strings.HasPrefix(national, code) {
+						return true
+					}
+				}
+				return false
+			}),
+		newPhoneRule("phone.region", "INVALID_AREA_CODE", entities.SeverityError,
+			regionMessage(profile.Phone), func(parsed parsedPhone) bool {
+				if len(states) == 0 {
+					return true
+				}
+				national, ok := mexicanNumber(parsed)
+				if !ok {
+					return false
+				}
+				location, exists := lookupAreaCode(national)
+				return exists && containsString(states, strings.ToLower(location.State))
+			}),
+	}, nil
+}
+
+// clientKeyFormatRule crea la regla de formato de la clave cliente: el patrón del perfil
+// o, si no hay patrón, solo números
+func clientKeyFormatRule(policy entities.ClientKeyPolicy) (validation.Rule, error) {
+	if policy.Pattern == "" {
+		return NewRule("client_key.format", entities.FieldClientKey, "INVALID_FORMAT", entities.SeverityError,
+			"La clave cliente debe contener solo números", func(value string) bool {
+				_, err := strconv.Atoi(value)
+				return err == nil
+			}), nil
+	}
+
+	pattern, err := regexp.Compile(policy.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("patrón de clave cliente no válido: %w", err)
+	}
+	return NewRule("client_key.format", entities.FieldClientKey, "INVALID_FORMAT", entities.SeverityError,
+		fmt.Sprintf("La clave cliente no tiene el formato esperado (%s)", policy.Pattern), pattern.MatchString), nil
+}
+
+// clientKeyLengthRule crea la regla de longitud de la clave cliente
+func clientKeyLengthRule(policy entities.ClientKeyPolicy) validation.Rule {
+	message := "La clave cliente no tiene la longitud esperada"
+	switch {
+	case policy.MinLength > 0 && policy.MinLength == policy.MaxLength:
+		message = fmt.Sprintf("La clave cliente debe tener %d caracteres", policy.MinLength)
+	case policy.MinLength > 0 && policy.MaxLength > 0:
+		message = fmt.Sprintf("La clave cliente debe tener entre %d y %d caracteres", policy.MinLength, policy.MaxLength)
+	case policy.MinLength > 0:
+		message = fmt.Sprintf("La clave cliente debe tener al menos %d caracteres", policy.MinLength)
+	case policy.MaxLength > 0:
+		message = fmt.Sprintf("La clave cliente debe tener como máximo %d caracteres", policy.MaxLength)
+	}
+
+	return NewRule("client_key.length", entities.FieldClientKey, "INVALID_LENGTH", entities.SeverityError,
+		message, func(value string) bool {
+			length := len([]rune(value))
+			return length >= policy.MinLength && (policy.MaxLength == 0 || length <= policy.MaxLength)
+		})
+}
+
+// nameCharactersRule crea la regla de caracteres del nombre según las clases del perfil
+func nameCharactersRule(policy entities.NamePolicy) (validation.Rule, error) {
+	var labels []string
+	var matchers []func(rune) bool
+	for _, class := range policy.CharacterClasses {
+		characterClass, exists := characterClasses[class]
+		if !exists {
+			return nil, fmt.Errorf("clase de caracteres desconocida: %s", class)
+		}
+		labels = append(labels, characterClass.label)
+		matchers = append(matchers, characterClass.matches)
+	}
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("el perfil debe indicar las clases de caracteres del nombre")
+	}
+
+	message := "El nombre debe contener solo " + labels[0]
+	if len(labels) > 1 {
+		message = "El nombre debe contener solo " + strings.Join(labels[:len(labels)-1], ", ") + " y " + labels[len(labels)-1]
+	}
+
+	return NewRule("name.characters", entities.FieldName, "INVALID_CHARACTER", entities.SeverityError,
+		message, func(value string) bool {
+			for _, char := range value {
+				allowed := false
+				for _, matches := range matchers {
+					if matches(char) {
+						allowed = true
+						break
+					}
+				}
+				if !allowed {
+					return false
+				}
+			}
+			return true
+		}), nil
+}
+
+// areaCodeMessage describe las ladas permitidas por el perfil
+func areaCodeMessage(policy entities.PhonePolicy) string {
+	codes := strings.Join(policy.AreaCodes, ", ")
+	if policy.Region != "" {
+		return fmt.Sprintf("La lada debe ser de %s (%s)", policy.Region, codes)
+	}
+	return fmt.Sprintf("La lada no está permitida (use %s)", codes)
+}
+
+// regionMessage describe los estados permitidos por el perfil
+func regionMessage(policy entities.PhonePolicy) string {
+	states := strings.Join(policy.States, ", ")
+	if policy.Region != "" {
+		return fmt.Sprintf("La lada debe ser de %s (%s)", policy.Region, states)
+	}
+	return fmt.Sprintf("La lada debe ser de %s", states)
+}
+
+// emailDomain obtiene el dominio de un email en minúsculas
+func emailDomain(email string) string {
+	return strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+}
+
+// lowerAll convierte a minúsculas todos los textos de la lista
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(strings.TrimSpace(value))
+	}
+	return lowered
+}
+
+// upperAll convierte a mayúsculas todos los textos de la lista
+func upperAll(values []string) []string {
+	uppered := make([]string, len(values))
+	for i, value := range values {
+		uppered[i] = strings.ToUpper(strings.TrimSpace(value))
+	}
+	return uppered
+}
+
+// mexicanNumber obtiene el número nacional de un teléfono de México con la longitud del plan
+func mexicanNumber(parsed parsedPhone) (string, bool) {
+	if parsed.Country != defaultPhoneCountry || !parsed.ValidLength() {
+		return "", false
+	}
+	return parsed.National, true
+}
+
+// notEmpty indica si el valor no está vacío
+func notEmpty(value string) bool {
+	return value != ""
+}
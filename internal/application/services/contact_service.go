@@ -1,6 +1,8 @@
 package services
 
 import (
+	"errors"
+
 	"analizador-backend/internal/domain/entities"
 	"analizador-backend/internal/domain/repositories"
 )
@@ -8,6 +10,8 @@ import (
 type ContactService struct {
 	contactRepo      repositories.ContactRepository
 	validatorService *ValidatorService
+	validations      *validationCache
+	workers          int
 }
 
 // NewContactService crea una nueva instancia del servicio de contactos
@@ -15,46 +19,272 @@ func NewContactService(contactRepo repositories.ContactRepository, validatorServ
 	return &ContactService{
 		contactRepo:      contactRepo,
 		validatorService: validatorService,
+		validations:      newValidationCache(),
 	}
 }
 
-// GetAllContacts obtiene todos los contactos
-func (s *ContactService) GetAllContacts() ([]*entities.Contact, error) {
-	return s.contactRepo.FindAll()
+// GetAllContacts obtiene todos los contactos de un dataset
+func (s *ContactService) GetAllContacts(datasetID string) ([]*entities.Contact, error) {
+	return s.contactRepo.FindAll(datasetID)
+}
+
+// GetContact obtiene un contacto verificando que pertenezca al dataset
+func (s *ContactService) GetContact(datasetID string, id int) (*entities.Contact, error) {
+	contact, err := s.contactRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if contact.DatasetID != datasetID {
+		return nil, errors.New("contacto no encontrado")
+	}
+	return contact, nil
 }
 
-// SearchContacts busca contactos por campo y valor
-func (s *ContactService) SearchContacts(field, value string) ([]*entities.Contact, error) {
-	return s.contactRepo.Search(field, value)
+// SearchContacts busca contactos de un dataset por campo y valor
+func (s *ContactService) SearchContacts(datasetID, field, value string) ([]*entities.Contact, error) {
+	return s.contactRepo.Search(datasetID, field, value)
 }
 
-// UpdateContact actualiza un contacto existente
-func (s *ContactService) UpdateContact(contact *entities.Contact) error {
+// UpdateContact actualiza un contacto existente del dataset conservando su origen y fecha de creación
+func (s *ContactService) UpdateContact(datasetID string, contact *entities.Contact) error {
+	existing, err := s.GetContact(datasetID, contact.ID)
+	if err != nil {
+		return err
+	}
+
+	contact.DatasetID = existing.DatasetID
+	contact.Source = existing.Source
+	contact.CreatedAt = existing.CreatedAt
+	enrichPhone(contact)
 	return s.contactRepo.Update(contact)
 }
 
-// ValidateAllContacts valida todos los contactos y retorna los resultados
-func (s *ContactService) ValidateAllContacts() ([]*entities.ContactWithValidation, error) {
-	contacts, err := s.contactRepo.FindAll()
+// UpdateContactsBatch actualiza varios contactos del dataset en una sola operación atómica
+func (s *ContactService) UpdateContactsBatch(datasetID string, contacts []*entities.Contact) error {
+	for _, contact := range contacts {
+		existing, err := s.GetContact(datasetID, contact.ID)
+		if err != nil {
+			return err
+		}
+		contact.DatasetID = existing.DatasetID
+		contact.Source = existing.Source
+		contact.CreatedAt = existing.CreatedAt
+		enrichPhone(contact)
+	}
+	return s.contactRepo.UpdateBatch(contacts)
+}
+
+// DeleteContact elimina un contacto del dataset
+func (s *ContactService) DeleteContact(datasetID string, id int) error {
+	if _, err := s.GetContact(datasetID, id); err != nil {
+		return err
+	}
+	return s.contactRepo.Delete(id)
+}
+
+// RestoreContact vuelve a guardar un contacto eliminado conservando su ID
+func (s *ContactService) RestoreContact(contact *entities.Contact) error {
+	return s.contactRepo.Save(contact)
+}
+
+// ValidateAllContacts valida todos los contactos de un dataset y retorna los resultados.
+// Los resultados se guardan en caché: si ningún contacto cambió desde la última validación
+// con el mismo perfil se retornan sin validar de nuevo, y si cambiaron algunos solo esos
+// vuelven a pasar por las reglas. Los resultados retornados no deben modificarse.
+func (s *ContactService) ValidateAllContacts(datasetID, profile string) ([]*entities.ContactWithValidation, error) {
+	validator, err := s.validatorService.ForProfile(profile)
 	if err != nil {
 		return nil, err
 	}
 
-	var results []*entities.ContactWithValidation
-	for _, contact := range contacts {
-		errors := s.validatorService.ValidateContact(contact)
-		result := &entities.ContactWithValidation{
-			Contact: *contact,
-			Errors:  errors,
-			IsValid: len(errors) == 0,
+	contacts, err := s.contactRepo.FindAll(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := s.validations.get(datasetID, validator)
+	if cached != nil && cached.unchanged(contacts) {
+		return append([]*entities.ContactWithValidation(nil), cached.results...), nil
+	}
+
+	ruleErrors := validateInParallel(contacts, s.workers, func(contact *entities.Contact) []entities.ValidationError {
+		if errors, ok := cached.ruleErrors(contact); ok {
+			return errors
 		}
-		results = append(results, result)
+		return validator.ValidateContact(contact)
+	})
+
+	validated := &cachedValidation{
+		validator: validator,
+		version:   validator.RulesVersion(),
+		contacts:  make(map[int]cachedContact, len(contacts)),
+		results:   validateContacts(validator, contacts, ruleErrors),
+	}
+	for i, contact := range contacts {
+		validated.contacts[contact.ID] = cachedContact{updatedAt: contact.UpdatedAt, errors: ruleErrors[i]}
+	}
+	s.validations.put(datasetID, validated)
+
+	return append([]*entities.ContactWithValidation(nil), validated.results...), nil
+}
+
+// ValidationSummary valida todos los contactos de un dataset y resume los errores por
+// campo, tipo y severidad con los top valores con errores más frecuentes de cada campo
+func (s *ContactService) ValidationSummary(datasetID, profile string, top int) (*entities.ValidationSummary, error) {
+	validator, err := s.validatorService.ForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.ValidateAllContacts(datasetID, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.ValidationSummary{
+		DatasetID: datasetID,
+		Profile:   validator.Profile().Name,
+		Stats:     SummarizeResults(results),
+		Fields:    SummarizeValidation(results, top),
+	}, nil
+}
+
+// ValidateContacts valida una lista de contactos sin necesidad de que estén guardados.
+// Además de las reglas por contacto, detecta claves cliente repetidas dentro de la lista.
+func (s *ContactService) ValidateContacts(contacts []*entities.Contact, profile string) ([]*entities.ContactWithValidation, error) {
+	validator, err := s.validatorService.ForProfile(profile)
+	if err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	ruleErrors := validateInParallel(contacts, s.workers, validator.ValidateContact)
+	return validateContacts(validator, contacts, ruleErrors), nil
 }
 
-// SaveContactsBatch guarda múltiples contactos
-func (s *ContactService) SaveContactsBatch(contacts []*entities.Contact) error {
+// SetValidationWorkers define cuántos workers validan los contactos en paralelo; cero usa
+// un worker por CPU
+func (s *ContactService) SetValidationWorkers(workers int) {
+	s.workers = workers
+}
+
+// validateContacts combina los errores de las reglas de cada contacto, en la posición
+// del contacto, con los errores que dependen de toda la lista (claves repetidas o sin
+// ceros a la izquierda)
+func validateContacts(validator *ValidatorService, contacts []*entities.Contact, ruleErrors [][]entities.ValidationError) []*entities.ContactWithValidation {
+	duplicates := validator.DuplicateKeyErrors(contacts)
+	shortKeys := validator.ClientKeyLengthErrors(contacts)
+
+	results := make([]*entities.ContactWithValidation, 0, len(contacts))
+	for i, contact := range contacts {
+		// Copiar los errores de las reglas para no modificar los guardados en caché
+		errors := append([]entities.ValidationError(nil), ruleErrors[i]...)
+		if shortKey, exists := shortKeys[i]; exists {
+			errors = append(errors, shortKey)
+		}
+		if duplicate, exists := duplicates[i]; exists {
+			errors = append(errors, duplicate)
+		}
+		results = append(results, NewValidationResult(contact, errors))
+	}
+
+	return results
+}
+
+// CountContacts obtiene el número de contactos de un dataset
+func (s *ContactService) CountContacts(datasetID string) (int, error) {
+	return s.contactRepo.Count(datasetID)
+}
+
+// DeleteAllContacts elimina todos los contactos de un dataset y sus validaciones guardadas
+func (s *ContactService) DeleteAllContacts(datasetID string) error {
+	s.validations.forget(datasetID)
+	return s.contactRepo.DeleteByDataset(datasetID)
+}
+
+// MoveContacts pasa todos los contactos de un dataset a otro; si alguno no se puede
+// mover no se mueve ninguno
+func (s *ContactService) MoveContacts(fromDatasetID, toDatasetID string) error {
+	s.validations.forget(fromDatasetID)
+	return s.contactRepo.MoveToDataset(fromDatasetID, toDatasetID)
+}
+
+// MoveContactsTo pasa varios contactos a otro dataset en una sola operación
+func (s *ContactService) MoveContactsTo(toDatasetID string, contacts []*entities.Contact) error {
+	moved := make([]*entities.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		s.validations.forget(contact.DatasetID)
+		copied := *contact
+		copied.DatasetID = toDatasetID
+		moved = append(moved, &copied)
+	}
+	return s.contactRepo.UpdateBatch(moved)
+}
+
+// ContactsPage obtiene hasta limit contactos de un dataset con ID mayor que afterID,
+// ordenados por ID, para recorrer el dataset por lotes
+func (s *ContactService) ContactsPage(datasetID string, afterID, limit int) ([]*entities.Contact, error) {
+	return s.contactRepo.FindPage(datasetID, afterID, limit)
+}
+
+// ContactsByClientKey agrupa por clave cliente los contactos de un dataset que tienen
+// alguna de las claves indicadas
+func (s *ContactService) ContactsByClientKey(datasetID string, clientKeys []string) (map[string][]*entities.Contact, error) {
+	contacts, err := s.contactRepo.FindByClientKeys(datasetID, clientKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string][]*entities.Contact, len(clientKeys))
+	for _, contact := range contacts {
+		byKey[contact.ClientKey] = append(byKey[contact.ClientKey], contact)
+	}
+	return byKey, nil
+}
+
+// ApplyUpsert pasa los contactos de changesID al dataset destino en una sola operación: los
+// que tienen la clave de un contacto del destino lo actualizan y los demás se insertan. Los
+// contactos del destino cuya clave no está en changesID ni en seenID se conservan, se marcan
+// o se eliminan según la política indicada. Retorna cuántos se marcaron y cuántos se eliminaron.
+func (s *ContactService) ApplyUpsert(changesID, seenID, datasetID, missing string) (int, int, error) {
+	absent := repositories.AbsentContacts{SeenDatasetID: seenID}
+	switch missing {
+	case entities.MissingPolicyMark:
+		absent.Status = entities.ContactStatusMissing
+	case entities.MissingPolicyRemove:
+		absent.Remove = true
+	}
+
+	s.validations.forget(changesID)
+	s.validations.forget(datasetID)
+	affected, err := s.contactRepo.MergeByClientKey(changesID, datasetID, absent)
+	if err != nil {
+		return 0, 0, err
+	}
+	if absent.Remove {
+		return 0, affected, nil
+	}
+	return affected, 0, nil
+}
+
+// SaveContactsBatch guarda múltiples contactos en un dataset
+func (s *ContactService) SaveContactsBatch(datasetID string, contacts []*entities.Contact) error {
+	for _, contact := range contacts {
+		contact.DatasetID = datasetID
+	}
 	return s.contactRepo.SaveBatch(contacts)
-}
\ No newline at end of file
+}
+
+// newRowOutcome crea el resultado de una fila a partir del origen del contacto
+func newRowOutcome(contact *entities.Contact) entities.RowOutcome {
+	row := entities.RowOutcome{ClientKey: contact.ClientKey}
+	if contact.Source != nil {
+		row.Sheet = contact.Source.Sheet
+		row.Row = contact.Source.Row
+	}
+	return row
+}
+
+// sameContactData indica si dos contactos tienen los mismos datos importables
+func sameContactData(a, b *entities.Contact) bool {
+	return a.ClientKey == b.ClientKey && a.Name == b.Name && a.Email == b.Email && a.Phone == b.Phone
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type DatasetService struct {
+	datasetRepo      repositories.DatasetRepository
+	contactService   *ContactService
+	mergeRecordRepo  repositories.MergeRecordRepository
+	validatorService *ValidatorService
+}
+
+// NewDatasetService crea una nueva instancia del servicio de datasets
+func NewDatasetService(datasetRepo repositories.DatasetRepository, contactService *ContactService, mergeRecordRepo repositories.MergeRecordRepository, validatorService *ValidatorService) *DatasetService {
+	return &DatasetService{
+		datasetRepo:      datasetRepo,
+		contactService:   contactService,
+		mergeRecordRepo:  mergeRecordRepo,
+		validatorService: validatorService,
+	}
+}
+
+// CreateDataset crea un dataset vacío para una carga con el perfil de validación
+// indicado; un perfil vacío usa el perfil por defecto
+func (s *DatasetService) CreateDataset(name, sourceFile, profile string) (*entities.Dataset, error) {
+	if name == "" {
+		name = sourceFile
+	}
+	if err := s.CheckProfile(profile); err != nil {
+		return nil, err
+	}
+
+	dataset := &entities.Dataset{
+		ID:         newID(),
+		Name:       name,
+		SourceFile: sourceFile,
+		Profile:    profile,
+	}
+	if err := s.datasetRepo.Save(dataset); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
+
+// GetDataset obtiene un dataset con su número de contactos
+func (s *DatasetService) GetDataset(id string) (*entities.Dataset, error) {
+	dataset, err := s.datasetRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withCount(dataset)
+}
+
+// LatestDataset obtiene el dataset cargado más recientemente con su número de contactos.
+// Lo usan las rutas anteriores a los datasets, que no indican uno.
+func (s *DatasetService) LatestDataset() (*entities.Dataset, error) {
+	datasets, err := s.datasetRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(datasets) == 0 {
+		return nil, errors.New("no hay datasets cargados")
+	}
+	return s.withCount(datasets[len(datasets)-1])
+}
+
+// GetAllDatasets obtiene todos los datasets con su número de contactos
+func (s *DatasetService) GetAllDatasets() ([]*entities.Dataset, error) {
+	datasets, err := s.datasetRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*entities.Dataset, 0, len(datasets))
+	for _, dataset := range datasets {
+		withCount, err := s.withCount(dataset)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, withCount)
+	}
+	return results, nil
+}
+
+// TouchDataset actualiza la fecha de modificación de un dataset
+func (s *DatasetService) TouchDataset(id string) error {
+	dataset, err := s.datasetRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	return s.datasetRepo.Update(dataset)
+}
+
+// SetProfile cambia el perfil de validación de un dataset; un perfil vacío usa el perfil por defecto
+func (s *DatasetService) SetProfile(id, profile string) (*entities.Dataset, error) {
+	if err := s.CheckProfile(profile); err != nil {
+		return nil, err
+	}
+
+	dataset, err := s.datasetRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset.Profile = profile
+	if err := s.datasetRepo.Update(dataset); err != nil {
+		return nil, err
+	}
+	return s.withCount(dataset)
+}
+
+// CheckProfile verifica que exista el perfil de validación; un perfil vacío es el perfil por defecto
+func (s *DatasetService) CheckProfile(profile string) error {
+	_, err := s.validatorService.ForProfile(profile)
+	return err
+}
+
+// ValidationProfile obtiene el perfil con el que se validan los contactos de un dataset:
+// el perfil solicitado o, si no se indica, el del dataset
+func (s *DatasetService) ValidationProfile(datasetID, requested string) (string, error) {
+	if requested != "" || datasetID == "" {
+		return requested, s.CheckProfile(requested)
+	}
+
+	dataset, err := s.datasetRepo.FindByID(datasetID)
+	if err != nil {
+		return "", err
+	}
+	return dataset.Profile, nil
+}
+
+// DeleteDataset elimina un dataset junto con todos sus contactos y registros de fusión
+func (s *DatasetService) DeleteDataset(id string) error {
+	if _, err := s.datasetRepo.FindByID(id); err != nil {
+		return err
+	}
+
+	if err := s.contactService.DeleteAllContacts(id); err != nil {
+		return err
+	}
+	if err := s.mergeRecordRepo.DeleteByDataset(id); err != nil {
+		return err
+	}
+	return s.datasetRepo.Delete(id)
+}
+
+// withCount retorna una copia del dataset con el número actual de contactos
+func (s *DatasetService) withCount(dataset *entities.Dataset) (*entities.Dataset, error) {
+	count, err := s.contactService.CountContacts(dataset.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *dataset
+	result.ContactCount = count
+	return &result, nil
+}
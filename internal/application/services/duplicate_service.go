@@ -0,0 +1,303 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// Peso de cada campo en la similitud entre dos contactos
+var duplicateFieldWeights = map[string]float64{
+	entities.FieldName:  0.4,
+	entities.FieldEmail: 0.3,
+	entities.FieldPhone: 0.3,
+}
+
+// maxPairwiseBlock es el tamaño máximo de un bloque en el que se comparan todos los pares
+const maxPairwiseBlock = 50
+
+// duplicateWindow es cuántos vecinos se comparan con cada contacto al recorrer un bloque
+// más grande que maxPairwiseBlock ordenado por sus valores normalizados
+const duplicateWindow = 10
+
+// minBlockToken es la longitud mínima de una palabra del nombre para usarla como bloque
+const minBlockToken = 3
+
+type DuplicateService struct {
+	contactService *ContactService
+}
+
+// NewDuplicateService crea una nueva instancia del servicio de detección de duplicados
+func NewDuplicateService(contactService *ContactService) *DuplicateService {
+	return &DuplicateService{
+		contactService: contactService,
+	}
+}
+
+// duplicateKeys contiene los valores normalizados de un contacto usados para compararlo
+type duplicateKeys struct {
+	name  string
+	email string
+	phone string
+}
+
+// FindDuplicates agrupa los contactos de un dataset que comparten nombre normalizado
+// (sin acentos, sin mayúsculas y sin importar el orden de las palabras), alguna palabra
+// del nombre con la misma pronunciación, dígitos del teléfono, email en minúsculas o
+// usuario del email. Dos contactos quedan en el mismo grupo solo si están enlazados por
+// pares con similitud de al menos minScore.
+func (s *DuplicateService) FindDuplicates(datasetID string, minScore float64) ([]*entities.DuplicateCluster, error) {
+	contacts, err := s.contactService.GetAllContacts(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]duplicateKeys, len(contacts))
+	sortKeys := make([]string, len(contacts))
+	blocks := make(map[string][]int)
+	for i, contact := range contacts {
+		keys[i] = duplicateKeys{
+			name:  normalizeName(contact.Name),
+			email: normalizeEmail(contact.Email),
+			phone: normalizePhoneDigits(contact.Phone),
+		}
+		sortKeys[i] = keys[i].name + "|" + keys[i].email + "|" + keys[i].phone
+		for _, key := range blockingKeys(keys[i]) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	// Unir solo los pares cuya similitud alcanza minScore; compartir un valor común (un
+	// nombre frecuente, por ejemplo) no basta para enlazar contactos distintos
+	parent := make([]int, len(contacts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	type pair struct{ a, b int }
+	type scoredPair struct {
+		pair
+		score  float64
+		fields []string
+	}
+	var links []scoredPair
+	linked := make(map[pair]bool)
+	compare := func(a, b int) {
+		if a > b {
+			a, b = b, a
+		}
+		candidate := pair{a, b}
+		if linked[candidate] {
+			return
+		}
+		score, fields := compareDuplicateKeys(keys[a], keys[b])
+		if score < minScore {
+			return
+		}
+		linked[candidate] = true
+		links = append(links, scoredPair{pair: candidate, score: score, fields: fields})
+		parent[find(b)] = find(a)
+	}
+
+	// Pares candidatos: contactos que comparten algún bloque. Los bloques grandes se
+	// ordenan por sus valores normalizados y cada contacto se compara solo con sus
+	// vecinos más cercanos para evitar un costo cuadrático.
+	for _, members := range blocks {
+		window := len(members)
+		if window > maxPairwiseBlock {
+			window = duplicateWindow + 1
+			sort.SliceStable(members, func(i, j int) bool {
+				return sortKeys[members[i]] < sortKeys[members[j]]
+			})
+		}
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members) && j < i+window; j++ {
+				compare(members[i], members[j])
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range contacts {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	// Puntuar cada grupo con el promedio de similitud de los pares enlazados
+	groupScores := make(map[int][]float64)
+	groupFields := make(map[int]map[string]bool)
+	for _, link := range links {
+		root := find(link.a)
+		groupScores[root] = append(groupScores[root], link.score)
+		if groupFields[root] == nil {
+			groupFields[root] = make(map[string]bool)
+		}
+		for _, field := range link.fields {
+			groupFields[root][field] = true
+		}
+	}
+
+	var clusters []*entities.DuplicateCluster
+	for root, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+
+		total := 0.0
+		for _, score := range groupScores[root] {
+			total += score
+		}
+		score := math.Round(total/float64(len(groupScores[root]))*1000) / 1000
+
+		cluster := &entities.DuplicateCluster{Score: score}
+		for _, index := range members {
+			cluster.ContactIDs = append(cluster.ContactIDs, contacts[index].ID)
+			cluster.Contacts = append(cluster.Contacts, contacts[index])
+		}
+		for _, field := range []string{entities.FieldName, entities.FieldEmail, entities.FieldPhone} {
+			if groupFields[root][field] {
+				cluster.MatchedFields = append(cluster.MatchedFields, field)
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Score != clusters[j].Score {
+			return clusters[i].Score > clusters[j].Score
+		}
+		return clusters[i].ContactIDs[0] < clusters[j].ContactIDs[0]
+	})
+	return clusters, nil
+}
+
+// blockingKeys obtiene los bloques de un contacto: el nombre completo, la pronunciación de
+// cada palabra del nombre, el email, el usuario del email y los dígitos del teléfono
+func blockingKeys(k duplicateKeys) []string {
+	var blockKeys []string
+	if k.name != "" {
+		blockKeys = append(blockKeys, "name:"+k.name)
+		seen := make(map[string]bool)
+		for _, token := range strings.Fields(k.name) {
+			if len([]rune(token)) < minBlockToken {
+				continue
+			}
+			code := phoneticKey(token)
+			if code != "" && !seen[code] {
+				seen[code] = true
+				blockKeys = append(blockKeys, "token:"+code)
+			}
+		}
+	}
+	if k.email != "" {
+		blockKeys = append(blockKeys, "email:"+k.email)
+		if at := strings.LastIndex(k.email, "@"); at > 0 {
+			blockKeys = append(blockKeys, "user:"+k.email[:at])
+		}
+	}
+	if k.phone != "" {
+		blockKeys = append(blockKeys, "phone:"+k.phone)
+	}
+	return blockKeys
+}
+
+// phoneticReplacer simplifica las grafías del español que suenan igual
+var phoneticReplacer = strings.NewReplacer(
+	"ch", "x", "ll", "y", "qu", "k", "gue", "ge", "gui", "gi",
+	"ce", "se", "ci", "si", "ge", "je", "gi", "ji",
+	"c", "k", "z", "s", "v", "b", "w", "b", "h", "", "ph", "f",
+)
+
+// phoneticKey obtiene una clave fonética de una palabra ya normalizada: unifica las
+// letras que suenan igual en español, quita las letras repetidas y conserva las vocales
+// solo al inicio, así "Gonzalez", "Gonsales" y "Gonzales" comparten clave
+func phoneticKey(token string) string {
+	sound := phoneticReplacer.Replace(token)
+
+	var key strings.Builder
+	var last rune
+	for i, char := range sound {
+		if char == last {
+			continue
+		}
+		last = char
+		if i > 0 && strings.ContainsRune("aeiouy", char) {
+			continue
+		}
+		key.WriteRune(char)
+	}
+	return key.String()
+}
+
+// compareDuplicateKeys calcula la similitud ponderada de dos contactos sobre los campos
+// que ambos tienen y retorna los campos cuyo valor normalizado coincide
+func compareDuplicateKeys(a, b duplicateKeys) (float64, []string) {
+	values := map[string][2]string{
+		entities.FieldName:  {a.name, b.name},
+		entities.FieldEmail: {a.email, b.email},
+		entities.FieldPhone: {a.phone, b.phone},
+	}
+
+	var matched []string
+	score, weights := 0.0, 0.0
+	for field, pair := range values {
+		if pair[0] == "" || pair[1] == "" {
+			continue
+		}
+
+		weight := duplicateFieldWeights[field]
+		weights += weight
+		if pair[0] == pair[1] {
+			score += weight
+			matched = append(matched, field)
+		} else if field == entities.FieldName {
+			score += weight * similarity(pair[0], pair[1])
+		}
+	}
+
+	if weights == 0 {
+		return 0, matched
+	}
+	return score / weights, matched
+}
+
+// normalizeName normaliza un nombre ignorando acentos, mayúsculas y el orden de las palabras
+func normalizeName(name string) string {
+	tokens := strings.Fields(normalizeText(name))
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// normalizeEmail normaliza un email a minúsculas sin espacios
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizePhoneDigits obtiene los últimos 10 dígitos de un teléfono, descartando
+// prefijos de país o de larga distancia; los números demasiado cortos se ignoran
+func normalizePhoneDigits(phone string) string {
+	var digits strings.Builder
+	for _, char := range phone {
+		if char >= '0' && char <= '9' {
+			digits.WriteRune(char)
+		}
+	}
+
+	value := digits.String()
+	if len(value) < 7 {
+		return ""
+	}
+	if len(value) > 10 {
+		value = value[len(value)-10:]
+	}
+	return value
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+func TestPhoneticKey(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		same   bool
+	}{
+		{tokens: []string{"gonzalez", "gonsales", "gonzales"}, same: true},
+		{tokens: []string{"hernandez", "ernandes"}, same: true},
+		{tokens: []string{"vazquez", "basques", "vasquez"}, same: true},
+		{tokens: []string{"guillermo", "guiyermo"}, same: true},
+		{tokens: []string{"gerardo", "jerardo"}, same: true},
+		{tokens: []string{"xochitl", "xochitl"}, same: true},
+		{tokens: []string{"perez", "lopez"}, same: false},
+		{tokens: []string{"ana", "ena"}, same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.tokens), func(t *testing.T) {
+			first := phoneticKey(tt.tokens[0])
+			for _, token := range tt.tokens[1:] {
+				if got := phoneticKey(token); (got == first) != tt.same {
+					t.Errorf("phoneticKey(%s) = %s, phoneticKey(%s) = %s; misma clave: %v", tt.tokens[0], first, token, got, tt.same)
+				}
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	// filler agrega contactos distintos que comparten el nombre de pila para formar un
+	// bloque más grande que maxPairwiseBlock
+	filler := func(count int) []*entities.Contact {
+		contacts := make([]*entities.Contact, count)
+		for i := range contacts {
+			contacts[i] = &entities.Contact{
+				Name:  fmt.Sprintf("Juan Apellido%c%c", 'a'+i/26, 'a'+i%26),
+				Email: fmt.Sprintf("juan%d@empresa.com", i),
+				Phone: fmt.Sprintf("96100%05d", i),
+			}
+		}
+		return contacts
+	}
+
+	tests := []struct {
+		name     string
+		contacts []*entities.Contact
+		minScore float64
+		// clusters lista las posiciones de los contactos de cada grupo esperado, en orden
+		clusters [][]int
+	}{
+		{
+			name: "acentos, mayúsculas y orden del nombre",
+			contacts: []*entities.Contact{
+				{Name: "José Pérez", Email: "jose@gmail.com"},
+				{Name: "PEREZ JOSE", Email: "JOSE@gmail.com "},
+				{Name: "María López", Email: "maria@gmail.com"},
+			},
+			minScore: 0.8,
+			clusters: [][]int{{0, 1}},
+		},
+		{
+			name: "mismo teléfono con prefijo de país",
+			contacts: []*entities.Contact{
+				{Name: "Ana Ruiz", Phone: "+52 961 123 4567"},
+				{Name: "Ana Ruiz", Phone: "9611234567"},
+			},
+			minScore: 0.8,
+			clusters: [][]int{{0, 1}},
+		},
+		{
+			name: "variantes de escritura del apellido",
+			contacts: []*entities.Contact{
+				{Name: "Luis Gonzalez", Phone: "9611234567"},
+				{Name: "Luis Gonsales", Phone: "9611234567"},
+				{Name: "Luis Martinez", Phone: "9617654321"},
+			},
+			minScore: 0.8,
+			clusters: [][]int{{0, 1}},
+		},
+		{
+			name: "un nombre común no basta para enlazar contactos distintos",
+			contacts: []*entities.Contact{
+				{Name: "Juan Pérez", Email: "juan1@gmail.com", Phone: "9611111111"},
+				{Name: "Juan Pérez", Email: "juan2@gmail.com", Phone: "9612222222"},
+			},
+			minScore: 0.8,
+		},
+		{
+			name: "duplicados dentro de un bloque grande",
+			contacts: append(filler(maxPairwiseBlock+20),
+				&entities.Contact{Name: "Juan Apellidoaa", Email: "juan0@empresa.com", Phone: "9610000000"},
+			),
+			minScore: 0.9,
+			clusters: [][]int{{0, maxPairwiseBlock + 20}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestImport(false)
+			if err := env.contacts.SaveContactsBatch("d", tt.contacts); err != nil {
+				t.Fatalf("SaveContactsBatch() error = %v", err)
+			}
+
+			clusters, err := NewDuplicateService(env.contacts).FindDuplicates("d", tt.minScore)
+			if err != nil {
+				t.Fatalf("FindDuplicates() error = %v", err)
+			}
+
+			got := make([][]int, len(clusters))
+			for i, cluster := range clusters {
+				got[i] = cluster.ContactIDs
+				if cluster.Score < tt.minScore || cluster.Score > 1 {
+					t.Errorf("grupo %v con puntuación %.3f fuera de [%.2f, 1]", cluster.ContactIDs, cluster.Score, tt.minScore)
+				}
+			}
+			want := make([][]int, len(tt.clusters))
+			for i, positions := range tt.clusters {
+				for _, position := range positions {
+					want[i] = append(want[i], tt.contacts[position].ID)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FindDuplicates() = %v, se esperaba %v", got, want)
+			}
+		})
+	}
+}
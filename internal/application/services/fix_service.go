@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+type FixService struct {
+	contactService   *ContactService
+	validatorService *ValidatorService
+}
+
+// NewFixService crea una nueva instancia del servicio de correcciones masivas
+func NewFixService(contactService *ContactService, validatorService *ValidatorService) *FixService {
+	return &FixService{
+		contactService:   contactService,
+		validatorService: validatorService,
+	}
+}
+
+// fixNormalizer representa una normalización automática que puede corregir un campo
+type fixNormalizer struct {
+	rule   string
+	fields []string
+	apply  func(string) string
+}
+
+// FixConflictError indica que una corrección aceptada ya no coincide con la propuesta
+// actual porque el contacto o el perfil cambiaron después de revisarla
+type FixConflictError struct {
+	Selection entities.FixSelection
+	Current   *entities.ContactFix
+}
+
+func (e *FixConflictError) Error() string {
+	if e.Current == nil {
+		return fmt.Sprintf("ya no hay una corrección propuesta para el campo %s del contacto %d", e.Selection.Field, e.Selection.ContactID)
+	}
+	return fmt.Sprintf("la corrección del campo %s del contacto %d cambió: ahora es '%s' -> '%s'",
+		e.Selection.Field, e.Selection.ContactID, e.Current.OldValue, e.Current.NewValue)
+}
+
+// fixNormalizers lista las normalizaciones automáticas en el orden en que se intentan,
+// antes de recurrir a las sugerencias de los errores de validación
+var fixNormalizers = []fixNormalizer{
+	{
+		rule:   entities.FixRuleTrimWhitespace,
+		fields: entities.ContactFields,
+		apply: func(value string) string {
+			return strings.Join(strings.Fields(value), " ")
+		},
+	},
+	{
+		rule:   entities.FixRuleLowercaseEmail,
+		fields: []string{entities.FieldEmail},
+		apply: func(value string) string {
+			return strings.ToLower(strings.Join(strings.Fields(value), ""))
+		},
+	},
+	{
+		rule:   entities.FixRuleNormalizePhone,
+		fields: []string{entities.FieldPhone},
+		apply:  normalizePhone,
+	},
+}
+
+// ProposeFixes calcula las correcciones propuestas para cada campo con errores o
+// advertencias de los contactos del dataset. Por cada campo se usa la primera normalización
+// automática que deja el valor sin errores o, si ninguna lo logra, la primera sugerencia.
+func (s *FixService) ProposeFixes(datasetID, profile string) ([]entities.ContactFix, error) {
+	validator, err := s.validatorService.ForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.contactService.ValidateAllContacts(datasetID, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixes []entities.ContactFix
+	for _, result := range results {
+		if len(result.Errors) == 0 {
+			continue
+		}
+
+		var fields []string
+		errorsByField := make(map[string][]entities.ValidationError)
+		for _, validationError := range result.Errors {
+			if _, exists := errorsByField[validationError.Field]; !exists {
+				fields = append(fields, validationError.Field)
+			}
+			errorsByField[validationError.Field] = append(errorsByField[validationError.Field], validationError)
+		}
+
+		for _, field := range fields {
+			if fix, ok := proposeFix(validator, &result.Contact, field, errorsByField[field]); ok {
+				fixes = append(fixes, fix)
+			}
+		}
+	}
+
+	return fixes, nil
+}
+
+// proposeFix busca la corrección de un campo del contacto a partir de sus errores
+func proposeFix(validator *ValidatorService, contact *entities.Contact, field string, fieldErrors []entities.ValidationError) (entities.ContactFix, bool) {
+	value := contactField(contact, field)
+	fix := entities.ContactFix{
+		ContactID: contact.ID,
+		Field:     field,
+		OldValue:  value,
+		ErrorType: fieldErrors[0].Type,
+	}
+
+	// Las normalizaciones solo se validan campo por campo, así que no corrigen los
+	// errores que dependen del resto del dataset (clave repetida o sin ceros)
+	if len(validator.ValidateField(field, value)) == len(fieldErrors) {
+		for _, normalizer := range fixNormalizers {
+			if !containsString(normalizer.fields, field) {
+				continue
+			}
+			candidate := normalizer.apply(value)
+			if candidate == value {
+				continue
+			}
+			// El candidato debe quedar sin errores y, si el valor solo tenía advertencias,
+			// resolver al menos una
+			candidateErrors := validator.ValidateField(field, candidate)
+			if !hasErrors(candidateErrors) && (hasErrors(fieldErrors) || len(candidateErrors) < len(fieldErrors)) {
+				fix.NewValue = candidate
+				fix.Rule = normalizer.rule
+				return fix, true
+			}
+		}
+	}
+
+	for _, validationError := range fieldErrors {
+		if len(validationError.Suggestions) > 0 {
+			fix.NewValue = validationError.Suggestions[0]
+			fix.Rule = entities.FixRuleSuggestion
+			fix.ErrorType = validationError.Type
+			return fix, true
+		}
+	}
+
+	return fix, false
+}
+
+// ApplyFixes recalcula las correcciones del dataset y aplica todas o solo las aceptadas
+// en una sola operación: si alguna no puede guardarse no se aplica ninguna. Una corrección
+// aceptada cuyos valores ya no coinciden con la propuesta actual se rechaza con
+// FixConflictError.
+func (s *FixService) ApplyFixes(datasetID, profile string, request entities.ApplyFixesRequest) (*entities.FixResult, error) {
+	if !request.All && len(request.Fixes) == 0 {
+		return nil, errors.New("indique las correcciones a aplicar o use 'all'")
+	}
+
+	proposed, err := s.ProposeFixes(datasetID, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := proposed
+	if !request.All {
+		type fixKey struct {
+			contactID int
+			field     string
+		}
+		byKey := make(map[fixKey]entities.ContactFix, len(proposed))
+		for _, fix := range proposed {
+			byKey[fixKey{fix.ContactID, fix.Field}] = fix
+		}
+
+		selected = nil
+		seen := make(map[fixKey]bool)
+		for _, selection := range request.Fixes {
+			key := fixKey{selection.ContactID, selection.Field}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			fix, exists := byKey[key]
+			if !exists {
+				return nil, &FixConflictError{Selection: selection}
+			}
+			if fix.OldValue != selection.OldValue || fix.NewValue != selection.NewValue {
+				return nil, &FixConflictError{Selection: selection, Current: &fix}
+			}
+			selected = append(selected, fix)
+		}
+	}
+
+	result := &entities.FixResult{Applied: []entities.ContactFix{}}
+	if len(selected) == 0 {
+		return result, nil
+	}
+
+	var contacts []*entities.Contact
+	byID := make(map[int]*entities.Contact)
+	for _, fix := range selected {
+		contact, exists := byID[fix.ContactID]
+		if !exists {
+			existing, err := s.contactService.GetContact(datasetID, fix.ContactID)
+			if err != nil {
+				return nil, err
+			}
+			copied := *existing
+			contact = &copied
+			byID[fix.ContactID] = contact
+			contacts = append(contacts, contact)
+		}
+		setContactField(contact, fix.Field, fix.NewValue)
+	}
+
+	if err := s.contactService.UpdateContactsBatch(datasetID, contacts); err != nil {
+		return nil, err
+	}
+
+	result.Applied = selected
+	result.Contacts = len(contacts)
+	return result, nil
+}
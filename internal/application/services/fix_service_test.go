@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/infrastructure/repositories"
+)
+
+// newTestFixes crea el servicio de correcciones sobre un dataset "d" con los contactos dados
+func newTestFixes(t *testing.T, contacts []*entities.Contact) (*FixService, *ContactService) {
+	t.Helper()
+	validator := nationalValidator(t)
+	contactService := NewContactService(repositories.NewInMemoryContactRepository(repositories.ContactRepositoryOptions{}), validator)
+	if err := contactService.SaveContactsBatch("d", contacts); err != nil {
+		t.Fatalf("SaveContactsBatch() error = %v", err)
+	}
+	return NewFixService(contactService, validator), contactService
+}
+
+func TestProposeFixes(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value string
+		// rule y fixed son la regla y el valor de la corrección; sin regla no hay corrección
+		rule  string
+		fixed string
+	}{
+		{name: "espacios alrededor del email", field: entities.FieldEmail, value: " ana@Gmail.com ", rule: entities.FixRuleTrimWhitespace, fixed: "ana@Gmail.com"},
+		{name: "espacios dentro del email", field: entities.FieldEmail, value: "ana @Gmail.com", rule: entities.FixRuleLowercaseEmail, fixed: "ana@gmail.com"},
+		{name: "teléfono extranjero sin código", field: entities.FieldPhone, value: "34 612 935 278", rule: entities.FixRuleNormalizePhone, fixed: "+34612935278"},
+		{name: "dominio mal escrito", field: entities.FieldEmail, value: "ana@gmial.com", rule: entities.FixRuleSuggestion, fixed: "ana@gmail.com"},
+		{name: "caracteres en el nombre", field: entities.FieldName, value: "Ana3 Ruiz", rule: entities.FixRuleSuggestion, fixed: "Ana Ruiz"},
+		{name: "clave con decimal", field: entities.FieldClientKey, value: "7.0", rule: entities.FixRuleSuggestion, fixed: "7"},
+		{name: "teléfono incompleto", field: entities.FieldPhone, value: "961835274"},
+		{name: "contacto válido", field: entities.FieldName, value: "Ana Ruiz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contact := &entities.Contact{ClientKey: "7", Name: "Ana", Email: "ana@gmail.com", Phone: "9618352741"}
+			setContactField(contact, tt.field, tt.value)
+			service, _ := newTestFixes(t, []*entities.Contact{contact})
+
+			fixes, err := service.ProposeFixes("d", "")
+			if err != nil {
+				t.Fatalf("ProposeFixes() error = %v", err)
+			}
+			var want []entities.ContactFix
+			if tt.rule != "" {
+				want = []entities.ContactFix{{ContactID: contact.ID, Field: tt.field, OldValue: tt.value, NewValue: tt.fixed, Rule: tt.rule}}
+			}
+			for i := range fixes {
+				fixes[i].ErrorType = ""
+			}
+			if !reflect.DeepEqual(fixes, want) {
+				t.Errorf("ProposeFixes() = %+v, se esperaba %+v", fixes, want)
+			}
+		})
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func(contacts []*entities.Contact) entities.ApplyFixesRequest
+		applied int
+		// conflict indica si la solicitud debe rechazarse con FixConflictError
+		conflict bool
+		err      bool
+	}{
+		{
+			name: "todas las correcciones",
+			request: func(contacts []*entities.Contact) entities.ApplyFixesRequest {
+				return entities.ApplyFixesRequest{All: true}
+			},
+			applied: 2,
+		},
+		{
+			name: "solo las aceptadas",
+			request: func(contacts []*entities.Contact) entities.ApplyFixesRequest {
+				return entities.ApplyFixesRequest{Fixes: []entities.FixSelection{
+					{ContactID: contacts[1].ID, Field: entities.FieldName, OldValue: "Beto3 Ruiz", NewValue: "Beto Ruiz"},
+					{ContactID: contacts[1].ID, Field: entities.FieldName, OldValue: "Beto3 Ruiz", NewValue: "Beto Ruiz"},
+				}}
+			},
+			applied: 1,
+		},
+		{
+			name: "corrección que cambió después de revisarla",
+			request: func(contacts []*entities.Contact) entities.ApplyFixesRequest {
+				return entities.ApplyFixesRequest{Fixes: []entities.FixSelection{
+					{ContactID: contacts[1].ID, Field: entities.FieldName, OldValue: "Beto3 Ruiz", NewValue: "Beto"},
+				}}
+			},
+			conflict: true,
+		},
+		{
+			name: "campo sin corrección propuesta",
+			request: func(contacts []*entities.Contact) entities.ApplyFixesRequest {
+				return entities.ApplyFixesRequest{Fixes: []entities.FixSelection{
+					{ContactID: contacts[0].ID, Field: entities.FieldName, OldValue: "Ana", NewValue: "Ana Ruiz"},
+				}}
+			},
+			conflict: true,
+		},
+		{
+			name: "solicitud vacía",
+			request: func(contacts []*entities.Contact) entities.ApplyFixesRequest {
+				return entities.ApplyFixesRequest{}
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts := []*entities.Contact{
+				{ClientKey: "1", Name: "Ana", Email: " ana@gmail.com", Phone: "9618352741"},
+				{ClientKey: "2", Name: "Beto3 Ruiz", Email: "beto@gmail.com", Phone: "9618352742"},
+			}
+			service, contactService := newTestFixes(t, contacts)
+
+			result, err := service.ApplyFixes("d", "", tt.request(contacts))
+			var conflictErr *FixConflictError
+			if errors.As(err, &conflictErr) != tt.conflict || (err != nil) != (tt.conflict || tt.err) {
+				t.Fatalf("ApplyFixes() error = %v, se esperaba conflicto: %v, error: %v", err, tt.conflict, tt.err)
+			}
+			if err == nil && (len(result.Applied) != tt.applied || result.Contacts != tt.applied) {
+				t.Errorf("ApplyFixes() aplicó %d correcciones en %d contactos, se esperaban %d", len(result.Applied), result.Contacts, tt.applied)
+			}
+
+			remaining, err := service.ProposeFixes("d", "")
+			if err != nil {
+				t.Fatalf("ProposeFixes() error = %v", err)
+			}
+			if len(remaining) != 2-tt.applied {
+				t.Errorf("quedan %d correcciones propuestas, se esperaban %d", len(remaining), 2-tt.applied)
+			}
+			if tt.applied > 0 {
+				if stored, _ := contactService.GetContact("d", contacts[1].ID); stored.Name != "Beto Ruiz" {
+					t.Errorf("nombre guardado = %q, se esperaba %q", stored.Name, "Beto Ruiz")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+	"analizador-backend/internal/domain/repositories"
+)
+
+// importJobTTL es el tiempo que se conserva un trabajo de importación después de terminar
+const importJobTTL = 24 * time.Hour
+
+// defaultImportJobWorkers es el número de trabajos de importación que se procesan a la vez
+const defaultImportJobWorkers = 2
+
+// jobReportRows es el número de filas omitidas o en conflicto que guarda el reporte de un
+// trabajo, por hoja y en total
+const jobReportRows = 100
+
+// ErrTooManyImportJobs indica que ya se procesa el máximo de trabajos de importación a la vez
+var ErrTooManyImportJobs = errors.New("ya se procesa el máximo de cargas en segundo plano; intente más tarde")
+
+type ImportJobService struct {
+	importService *ImportService
+	jobRepo       repositories.ImportJobRepository
+	// cancels guarda la función de cancelación de los trabajos en curso; es nil desde que
+	// el trabajo empieza a guardar los contactos y ya no se puede cancelar
+	cancels map[string]context.CancelFunc
+	// slots limita los trabajos que se procesan a la vez: cada trabajo ocupa un lugar
+	// desde que se crea hasta que termina
+	slots chan struct{}
+	mutex sync.Mutex
+}
+
+// NewImportJobService crea una nueva instancia del servicio de trabajos de importación
+func NewImportJobService(importService *ImportService, jobRepo repositories.ImportJobRepository) *ImportJobService {
+	return &ImportJobService{
+		importService: importService,
+		jobRepo:       jobRepo,
+		cancels:       make(map[string]context.CancelFunc),
+		slots:         make(chan struct{}, defaultImportJobWorkers),
+	}
+}
+
+// SetImportJobWorkers define cuántos trabajos de importación se procesan a la vez; un valor
+// menor que uno usa el valor por defecto. Debe llamarse antes de crear trabajos.
+func (s *ImportJobService) SetImportJobWorkers(workers int) {
+	if workers < 1 {
+		workers = defaultImportJobWorkers
+	}
+	s.slots = make(chan struct{}, workers)
+}
+
+// Start revisa las opciones de una carga y la procesa en segundo plano. El trabajo se
+// vuelve dueño del libro y lo cierra al terminar; si la carga no es válida retorna un
+// UploadReadError sin crear el trabajo y el libro queda abierto; lo mismo pasa con
+// ErrTooManyImportJobs si ya se procesa el máximo de trabajos a la vez.
+func (s *ImportJobService) Start(workbook readers.Workbook, fileName, datasetID, datasetName string, options entities.ImportOptions) (*entities.ImportJob, error) {
+	if err := s.importService.CheckUpload(workbook, datasetID, options); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return nil, ErrTooManyImportJobs
+	}
+
+	job := &entities.ImportJob{
+		ID:        newID(),
+		Status:    entities.ImportJobPending,
+		DatasetID: datasetID,
+		FileName:  fileName,
+		Options:   options,
+		Errors:    []string{},
+		CreatedAt: time.Now(),
+	}
+	if err := s.jobRepo.Save(job); err != nil {
+		<-s.slots
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mutex.Lock()
+	s.cancels[job.ID] = cancel
+	s.mutex.Unlock()
+
+	go s.run(ctx, cancel, job, workbook, datasetName)
+	return s.jobRepo.FindByID(job.ID)
+}
+
+// run procesa la carga de un trabajo y guarda su avance después de cada lote
+func (s *ImportJobService) run(ctx context.Context, cancel context.CancelFunc, job *entities.ImportJob, workbook readers.Workbook, datasetName string) {
+	defer func() { <-s.slots }()
+	defer workbook.Close()
+	defer cancel()
+	defer s.release(job.ID)
+
+	started := time.Now()
+	job.Status = entities.ImportJobRunning
+	job.StartedAt = &started
+	s.save(ctx, job)
+
+	commit, err := s.importService.Upload(ctx, workbook, job.FileName, job.DatasetID, datasetName, job.Options,
+		func(progress UploadProgress) {
+			job.TotalRows = progress.TotalRows
+			job.RowsProcessed = progress.RowsRead
+			job.Imported = progress.Imported
+			job.Skipped = progress.Skipped
+			job.Progress = progress.Percent
+			if progress.Saving {
+				s.startSaving(job.ID)
+			}
+			s.save(ctx, job)
+		})
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.Status = entities.ImportJobCancelled
+		job.Imported = 0
+	case err != nil:
+		job.Status = entities.ImportJobFailed
+		job.Imported = 0
+		job.Errors = append(job.Errors, err.Error())
+	default:
+		job.Status = entities.ImportJobCompleted
+		job.DatasetID = commit.Dataset.ID
+		job.Report = newJobReport(commit)
+		job.RowsProcessed = commit.Result.Report.RowsRead
+		job.Imported = commit.Result.Report.Imported
+		job.Skipped = commit.Result.Report.Skipped
+		job.TotalRows = commit.Result.Report.RowsRead
+		job.Progress = 100
+		for _, skipped := range commit.Result.SkippedSheets {
+			job.Errors = append(job.Errors, fmt.Sprintf("Hoja %s omitida: %s", skipped.Sheet, skipped.Reason))
+		}
+	}
+
+	finished := time.Now()
+	expires := finished.Add(importJobTTL)
+	job.FinishedAt = &finished
+	job.ExpiresAt = &expires
+	s.save(ctx, job)
+}
+
+// newJobReport resume el resultado de una carga para guardarlo con el trabajo, recortando
+// las listas de filas a jobReportRows
+func newJobReport(commit *entities.ImportCommit) *entities.ImportJobReport {
+	result := commit.Result
+	sheets := make([]*entities.SheetImport, len(result.Sheets))
+	for i, sheet := range result.Sheets {
+		sheets[i] = &entities.SheetImport{
+			Sheet:   sheet.Sheet,
+			Mapping: sheet.Mapping,
+			Report:  boundedReport(sheet.Report),
+		}
+	}
+
+	outcome := *commit.Outcome
+	outcome.Rows = append([]entities.RowOutcome{}, outcome.Rows[:min(len(outcome.Rows), jobReportRows)]...)
+
+	return &entities.ImportJobReport{
+		UploadID:      result.UploadID,
+		Format:        result.Format,
+		Report:        boundedReport(result.Report),
+		Sheets:        sheets,
+		SkippedSheets: result.SkippedSheets,
+		Outcome:       &outcome,
+		Validation:    commit.Validation,
+	}
+}
+
+// boundedReport copia un reporte con solo las primeras jobReportRows filas omitidas. Las
+// filas se copian para no retener la lista completa de la carga.
+func boundedReport(report *entities.ImportReport) *entities.ImportReport {
+	bounded := *report
+	bounded.SkippedRows = append([]entities.SkippedRow{}, report.SkippedRows[:min(len(report.SkippedRows), jobReportRows)]...)
+	return &bounded
+}
+
+// save guarda el estado del trabajo indicando si se solicitó su cancelación. Se guarda con
+// el mutex del servicio para no mezclarse con una cancelación que se registra a la vez.
+func (s *ImportJobService) save(ctx context.Context, job *entities.ImportJob) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job.CancelRequested = ctx.Err() != nil
+	s.jobRepo.Save(job)
+}
+
+// startSaving impide cancelar un trabajo que ya terminó de leer el archivo. Una
+// cancelación registrada antes sigue vigente: la carga la revisa después de este aviso.
+func (s *ImportJobService) startSaving(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, running := s.cancels[id]; running {
+		s.cancels[id] = nil
+	}
+}
+
+// release descarta la función de cancelación de un trabajo que terminó
+func (s *ImportJobService) release(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.cancels, id)
+}
+
+// GetJob obtiene el estado de un trabajo de importación
+func (s *ImportJobService) GetJob(id string) (*entities.ImportJob, error) {
+	return s.jobRepo.FindByID(id)
+}
+
+// GetJobs lista los trabajos de importación, del más reciente al más antiguo, sin su
+// reporte; el reporte de un trabajo se obtiene con GetJob
+func (s *ImportJobService) GetJobs() ([]*entities.ImportJob, error) {
+	jobs, err := s.jobRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		job.Report = nil
+	}
+	return jobs, nil
+}
+
+// CancelJob solicita la cancelación de un trabajo en curso. La carga se detiene antes de
+// guardar el siguiente lote y lo guardado hasta ese momento se descarta. Un trabajo que
+// ya terminó de leer el archivo y está guardando los contactos no se puede cancelar.
+func (s *ImportJobService) CancelJob(id string) (*entities.ImportJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, err := s.jobRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cancel, running := s.cancels[id]
+	if !running || job.Finished() {
+		return nil, fmt.Errorf("el trabajo de importación ya terminó (%s)", job.Status)
+	}
+	if cancel == nil {
+		return nil, errors.New("el trabajo de importación ya está guardando los contactos y no se puede cancelar")
+	}
+
+	cancel()
+	job.CancelRequested = true
+	if err := s.jobRepo.Save(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/infrastructure/repositories"
+)
+
+// blockingWorkbook es un libro que no entrega sus filas hasta que se cierra release
+type blockingWorkbook struct {
+	rowsWorkbook
+	release chan struct{}
+	closed  atomic.Bool
+}
+
+func newBlockingWorkbook(rows rowsWorkbook) *blockingWorkbook {
+	return &blockingWorkbook{rowsWorkbook: rows, release: make(chan struct{})}
+}
+
+func (w *blockingWorkbook) Rows(sheet string) ([][]string, error) {
+	<-w.release
+	return w.rowsWorkbook, nil
+}
+
+func (w *blockingWorkbook) Close() error {
+	w.closed.Store(true)
+	return nil
+}
+
+// waitJob espera a que un trabajo de importación termine
+func waitJob(t *testing.T, jobs *ImportJobService, id string) *entities.ImportJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := jobs.GetJob(id)
+		if err != nil {
+			t.Fatalf("GetJob() error = %v", err)
+		}
+		if job.Finished() {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("el trabajo %s no terminó", id)
+	return nil
+}
+
+func TestImportJobReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		valid    int
+		invalid  int
+		options  entities.ImportOptions
+		imported int
+		// conflicts es el total de conflictos, que cuenta todas las filas aunque el reporte se recorte
+		conflicts int
+		// rows es el número de filas omitidas y en conflicto que guarda el reporte
+		rows int
+	}{
+		{name: "pocas filas omitidas", valid: 5, invalid: 3, imported: 5, rows: 3},
+		{name: "filas omitidas recortadas", valid: 5, invalid: jobReportRows + 50, imported: 5, rows: jobReportRows},
+		{
+			name:      "conflictos recortados",
+			valid:     jobReportRows + 50,
+			options:   entities.ImportOptions{Mode: entities.ImportModeUpsert},
+			imported:  jobReportRows + 50,
+			conflicts: jobReportRows + 50,
+			rows:      jobReportRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestImport(false)
+			jobs := NewImportJobService(env.imports, repositories.NewInMemoryImportJobRepository())
+
+			rows := contactRows()
+			for i := 0; i < tt.valid; i++ {
+				// En modo upsert las filas sin clave son conflictos
+				key := fmt.Sprint(i)
+				if tt.options.Mode == entities.ImportModeUpsert {
+					key = ""
+				}
+				rows = append(rows, []string{key, "Ana", "ana@gmail.com", "9618352741"})
+			}
+			for i := 0; i < tt.invalid; i++ {
+				rows = append(rows, []string{fmt.Sprint(i), "#REF!", "", ""})
+			}
+			workbook := newBlockingWorkbook(rows)
+			close(workbook.release)
+
+			started, err := jobs.Start(workbook, "carga.csv", "", "carga", tt.options)
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			job := waitJob(t, jobs, started.ID)
+
+			if job.Status != entities.ImportJobCompleted || job.Report == nil {
+				t.Fatalf("trabajo = %s %v, se esperaba completado con reporte", job.Status, job.Errors)
+			}
+			if job.Imported != tt.imported || job.Skipped != tt.invalid || job.Progress != 100 {
+				t.Errorf("trabajo = %d importadas, %d omitidas, %d%%; se esperaban %d, %d y 100%%", job.Imported, job.Skipped, job.Progress, tt.imported, tt.invalid)
+			}
+			report := job.Report
+			if report.Report.Skipped != tt.invalid || report.Outcome.Conflicts != tt.conflicts {
+				t.Errorf("reporte = %d omitidas y %d conflictos, se esperaban %d y %d", report.Report.Skipped, report.Outcome.Conflicts, tt.invalid, tt.conflicts)
+			}
+			if got := len(report.Report.SkippedRows) + len(report.Outcome.Rows); got != tt.rows {
+				t.Errorf("el reporte guarda %d filas, se esperaban %d", got, tt.rows)
+			}
+			if len(report.Sheets) != 1 || len(report.Sheets[0].Report.SkippedRows) > jobReportRows {
+				t.Errorf("el reporte de la hoja no se recortó: %+v", report.Sheets)
+			}
+			if !workbook.closed.Load() {
+				t.Errorf("el trabajo no cerró el libro")
+			}
+
+			listed, err := jobs.GetJobs()
+			if err != nil || len(listed) != 1 || listed[0].Report != nil {
+				t.Errorf("GetJobs() = %+v, %v; se esperaba el trabajo sin reporte", listed, err)
+			}
+			if job, _ := jobs.GetJob(started.ID); job.Report == nil {
+				t.Errorf("GetJobs() quitó el reporte del trabajo guardado")
+			}
+		})
+	}
+}
+
+func TestImportJobLimitAndCancel(t *testing.T) {
+	env := newTestImport(false)
+	jobs := NewImportJobService(env.imports, repositories.NewInMemoryImportJobRepository())
+	jobs.SetImportJobWorkers(1)
+
+	blocked := newBlockingWorkbook(contactRows("1", "Ana"))
+	started, err := jobs.Start(blocked, "carga.csv", "", "carga", entities.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Con el único lugar ocupado se rechaza otra carga sin cerrar su libro
+	rejected := newBlockingWorkbook(contactRows("2", "Beto"))
+	if _, err := jobs.Start(rejected, "otra.csv", "", "otra", entities.ImportOptions{}); !errors.Is(err, ErrTooManyImportJobs) {
+		t.Fatalf("Start() error = %v, se esperaba ErrTooManyImportJobs", err)
+	}
+	if rejected.closed.Load() {
+		t.Errorf("la carga rechazada cerró el libro")
+	}
+
+	// Unas opciones inválidas se rechazan antes de ocupar un lugar
+	var readErr *UploadReadError
+	if _, err := jobs.Start(rejected, "otra.csv", "", "otra", entities.ImportOptions{Sheet: "no existe"}); !errors.As(err, &readErr) {
+		t.Errorf("Start() error = %v, se esperaba UploadReadError", err)
+	}
+
+	cancelled, err := jobs.CancelJob(started.ID)
+	if err != nil || !cancelled.CancelRequested {
+		t.Fatalf("CancelJob() = %+v, %v", cancelled, err)
+	}
+	close(blocked.release)
+	job := waitJob(t, jobs, started.ID)
+	if job.Status != entities.ImportJobCancelled || job.Imported != 0 || job.Report != nil {
+		t.Errorf("trabajo = %s con %d importadas, se esperaba cancelado sin contactos", job.Status, job.Imported)
+	}
+	if datasets, _ := env.datasets.GetAllDatasets(); len(datasets) != 0 {
+		t.Errorf("la carga cancelada dejó %d datasets", len(datasets))
+	}
+	if _, err := jobs.CancelJob(started.ID); err == nil {
+		t.Errorf("CancelJob() de un trabajo terminado no retornó error")
+	}
+
+	// El lugar se libera cuando el trabajo termina
+	close(rejected.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		next, err := jobs.Start(rejected, "otra.csv", "", "otra", entities.ImportOptions{})
+		if err == nil {
+			if job := waitJob(t, jobs, next.ID); job.Status != entities.ImportJobCompleted || job.Imported != 1 {
+				t.Errorf("trabajo = %s con %d importadas, se esperaba completado con 1", job.Status, job.Imported)
+			}
+			break
+		}
+		if !errors.Is(err, ErrTooManyImportJobs) || time.Now().After(deadline) {
+			t.Fatalf("Start() error = %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,598 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+	"analizador-backend/internal/domain/repositories"
+)
+
+// minMappingScore es la puntuación mínima para aceptar una columna detectada
+const minMappingScore = 0.6
+
+// defaultHeaderSynonyms contiene los encabezados conocidos para cada campo de Contact
+var defaultHeaderSynonyms = map[string][]string{
+	entities.FieldClientKey: {
+		"clave cliente", "clave de cliente", "clave", "cve cliente", "id cliente",
+		"numero cliente", "numero de cliente", "no cliente", "codigo cliente",
+		"cliente", "client key", "customer id", "id",
+	},
+	entities.FieldName: {
+		"nombre contacto", "nombre del contacto", "nombre", "nombre completo",
+		"contacto", "razon social", "name", "full name", "contact name",
+	},
+	entities.FieldEmail: {
+		"correo", "correo electronico", "email", "e mail", "mail",
+		"email address", "correo contacto",
+	},
+	entities.FieldPhone: {
+		"telefono contacto", "telefono", "tel", "celular", "movil",
+		"numero telefono", "numero de telefono", "whatsapp", "phone",
+		"telephone", "mobile",
+	},
+}
+
+// stagedImportTTL es el tiempo que una carga en vista previa espera confirmación
+const stagedImportTTL = 30 * time.Minute
+
+type ImportService struct {
+	contactService   *ContactService
+	datasetService   *DatasetService
+	stagedImportRepo repositories.StagedImportRepository
+	synonyms         map[string][]string
+}
+
+// NewImportService crea una nueva instancia del servicio de importación
+func NewImportService(contactService *ContactService, datasetService *DatasetService, stagedImportRepo repositories.StagedImportRepository) *ImportService {
+	synonyms := make(map[string][]string, len(defaultHeaderSynonyms))
+	for field, values := range defaultHeaderSynonyms {
+		for _, value := range values {
+			synonyms[field] = append(synonyms[field], normalizeText(value))
+		}
+	}
+
+	return &ImportService{
+		contactService:   contactService,
+		datasetService:   datasetService,
+		stagedImportRepo: stagedImportRepo,
+		synonyms:         synonyms,
+	}
+}
+
+// normalizeOptions aplica los valores por defecto y valida el modo de importación
+func normalizeOptions(options entities.ImportOptions) (entities.ImportOptions, error) {
+	options.Mode = strings.ToLower(strings.TrimSpace(options.Mode))
+	options.Missing = strings.ToLower(strings.TrimSpace(options.Missing))
+	options.Profile = strings.TrimSpace(options.Profile)
+
+	switch options.Mode {
+	case "":
+		options.Mode = entities.ImportModeAppend
+	case entities.ImportModeAppend, entities.ImportModeUpsert:
+	default:
+		return options, fmt.Errorf("modo de importación no válido: %s", options.Mode)
+	}
+
+	switch options.Missing {
+	case "":
+		options.Missing = entities.MissingPolicyKeep
+	case entities.MissingPolicyKeep, entities.MissingPolicyMark, entities.MissingPolicyRemove:
+	default:
+		return options, fmt.Errorf("tratamiento de contactos ausentes no válido: %s", options.Missing)
+	}
+
+	return options, nil
+}
+
+// Preview lee un archivo igual que una carga, guardando sus filas en un dataset
+// provisional, y la deja en espera de confirmación. Retorna los primeros limit contactos
+// con sus errores de validación y las estadísticas de todo el archivo. Al confirmarse, los
+// contactos se guardan en datasetID o en un dataset nuevo si está vacío.
+func (s *ImportService) Preview(ctx context.Context, workbook readers.Workbook, fileName, datasetID, datasetName string, options entities.ImportOptions, limit int) (*entities.ImportPreview, error) {
+	s.purgeExpiredStaged()
+
+	load, err := s.readUpload(ctx, workbook, fileName, datasetID, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.stage(load, datasetID, datasetName, limit)
+	if err != nil {
+		load.discard()
+		return nil, err
+	}
+	return preview, nil
+}
+
+// stage valida las filas leídas de una carga y la guarda en espera de confirmación
+func (s *ImportService) stage(load *batchLoad, datasetID, datasetName string, limit int) (*entities.ImportPreview, error) {
+	profile, err := s.datasetService.ValidationProfile(datasetID, load.options.Profile)
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := s.contactService.GetAllContacts(load.pendingID)
+	if err != nil {
+		return nil, err
+	}
+	validations, err := s.contactService.ValidateContacts(contacts, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	staged := &entities.StagedImport{
+		Token:       newID(),
+		DatasetID:   datasetID,
+		DatasetName: datasetName,
+		PendingID:   load.pendingID,
+		Result:      load.result,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(stagedImportTTL),
+	}
+	if err := s.stagedImportRepo.Save(staged); err != nil {
+		return nil, err
+	}
+
+	if limit > len(validations) {
+		limit = len(validations)
+	}
+
+	return &entities.ImportPreview{
+		Token:     staged.Token,
+		ExpiresAt: staged.ExpiresAt,
+		Result:    load.result,
+		Stats:     SummarizeResults(validations),
+		Contacts:  validations[:limit],
+	}, nil
+}
+
+// ConfirmStaged guarda los contactos de una carga en vista previa y la elimina de la espera
+func (s *ImportService) ConfirmStaged(ctx context.Context, token string) (*entities.ImportCommit, error) {
+	staged, err := s.stagedImportRepo.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Eliminar primero evita que dos confirmaciones simultáneas guarden la carga dos veces
+	if err := s.stagedImportRepo.Delete(token); err != nil {
+		return nil, err
+	}
+
+	load := s.newBatchLoad(ctx, staged.Result, staged.PendingID)
+	load.imported = staged.Result.Report.Imported
+	commit, err := load.commit(staged.DatasetID, staged.DatasetName)
+	if err != nil {
+		// La carga sigue en espera mientras sus contactos no se hayan guardado
+		if !load.applied {
+			load.restore()
+			s.stagedImportRepo.Save(staged)
+		}
+		return nil, err
+	}
+	return commit, nil
+}
+
+// DiscardStaged descarta una carga en vista previa sin guardar sus contactos
+func (s *ImportService) DiscardStaged(token string) error {
+	staged, err := s.stagedImportRepo.FindByToken(token)
+	if err != nil {
+		return err
+	}
+	if err := s.stagedImportRepo.Delete(token); err != nil {
+		return err
+	}
+	return s.contactService.DeleteAllContacts(staged.PendingID)
+}
+
+// purgeExpiredStaged descarta las cargas en vista previa que vencieron sin confirmarse
+func (s *ImportService) purgeExpiredStaged() {
+	expired, err := s.stagedImportRepo.DeleteExpired(time.Now())
+	if err != nil {
+		return
+	}
+	for _, staged := range expired {
+		s.contactService.DeleteAllContacts(staged.PendingID)
+	}
+}
+
+// DetectColumns determina qué columna corresponde a cada campo de Contact.
+// Las asignaciones explícitas (campo -> encabezado o letra de columna) tienen
+// prioridad; el resto se detecta comparando el header con el diccionario de
+// sinónimos y, si no hay coincidencia, se usa la posición por defecto (A–D).
+func (s *ImportService) DetectColumns(header []string, overrides map[string]string) ([]entities.ColumnMapping, error) {
+	assignedFields := make(map[string]entities.ColumnMapping)
+	usedColumns := make(map[int]bool)
+
+	// Asignaciones explícitas, en orden de campo para que el error reportado no dependa
+	// del orden del mapa
+	overrideFields := make([]string, 0, len(overrides))
+	for field := range overrides {
+		overrideFields = append(overrideFields, field)
+	}
+	sort.Strings(overrideFields)
+	for _, field := range overrideFields {
+		target := overrides[field]
+		if _, known := s.synonyms[field]; !known {
+			return nil, fmt.Errorf("campo desconocido en el mapeo: %s", field)
+		}
+
+		index, ok := s.resolveColumn(header, target)
+		if !ok {
+			return nil, fmt.Errorf("no se encontró la columna '%s' para el campo %s", target, field)
+		}
+		if usedColumns[index] {
+			return nil, fmt.Errorf("la columna %s está asignada a más de un campo", columnName(index))
+		}
+
+		assignedFields[field] = s.newMapping(field, header, index, 1, entities.MappingSourceExplicit)
+		usedColumns[index] = true
+	}
+
+	// Detección por encabezado: se asignan primero las coincidencias con mayor puntuación
+	type candidate struct {
+		field string
+		index int
+		score float64
+	}
+	var candidates []candidate
+	for _, field := range entities.ContactFields {
+		if _, assigned := assignedFields[field]; assigned {
+			continue
+		}
+		for index, cell := range header {
+			if usedColumns[index] {
+				continue
+			}
+			if score := s.headerScore(field, cell); score >= minMappingScore {
+				candidates = append(candidates, candidate{field: field, index: index, score: score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	for _, c := range candidates {
+		if _, assigned := assignedFields[c.field]; assigned || usedColumns[c.index] {
+			continue
+		}
+		assignedFields[c.field] = s.newMapping(c.field, header, c.index, c.score, entities.MappingSourceDetected)
+		usedColumns[c.index] = true
+	}
+
+	// Posición por defecto para los campos que no se pudieron detectar
+	for position, field := range entities.ContactFields {
+		if _, assigned := assignedFields[field]; assigned || usedColumns[position] {
+			continue
+		}
+		assignedFields[field] = s.newMapping(field, header, position, 0, entities.MappingSourceDefault)
+		usedColumns[position] = true
+	}
+
+	mapping := make([]entities.ColumnMapping, 0, len(assignedFields))
+	for _, field := range entities.ContactFields {
+		if m, assigned := assignedFields[field]; assigned {
+			mapping = append(mapping, m)
+		}
+	}
+	return mapping, nil
+}
+
+// NewUpload crea el origen común de los contactos de una carga con un identificador único
+func (s *ImportService) NewUpload(fileName string) entities.ContactSource {
+	return entities.ContactSource{
+		UploadID: newID(),
+		FileName: fileName,
+	}
+}
+
+// SelectSheets resuelve qué hojas importar. El selector puede ser el nombre de una
+// hoja, su índice (base 0) o "*" / "all" para todas las hojas; vacío selecciona la primera.
+func (s *ImportService) SelectSheets(sheets []string, selector string) ([]string, error) {
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("el archivo no contiene hojas")
+	}
+
+	selector = strings.TrimSpace(selector)
+	switch strings.ToLower(selector) {
+	case "":
+		return sheets[:1], nil
+	case "*", "all":
+		return sheets, nil
+	}
+
+	for _, sheet := range sheets {
+		if sheet == selector {
+			return []string{sheet}, nil
+		}
+	}
+	for _, sheet := range sheets {
+		if strings.EqualFold(strings.TrimSpace(sheet), selector) {
+			return []string{sheet}, nil
+		}
+	}
+	if index, err := strconv.Atoi(selector); err == nil && index >= 0 && index < len(sheets) {
+		return []string{sheets[index]}, nil
+	}
+
+	return nil, fmt.Errorf("no se encontró la hoja '%s'", selector)
+}
+
+// IsAllSheets indica si el selector corresponde al modo de todas las hojas
+func (s *ImportService) IsAllSheets(selector string) bool {
+	selector = strings.ToLower(strings.TrimSpace(selector))
+	return selector == "*" || selector == "all"
+}
+
+// InspectSheet describe una hoja: número de registros, encabezados y columnas detectadas.
+// La hoja se recorre fila por fila para contar los registros sin cargarla completa.
+func (s *ImportService) InspectSheet(workbook readers.Workbook, index int, name string) (entities.SheetInfo, error) {
+	info := entities.SheetInfo{
+		Index:   index,
+		Name:    name,
+		Headers: []string{},
+		Mapping: []entities.ColumnMapping{},
+	}
+
+	rows, err := OpenRows(workbook, name)
+	if err != nil {
+		return info, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return info, nil
+	}
+	header, err := rows.Columns()
+	if err != nil {
+		return info, err
+	}
+
+	// Las filas vacías al final de la hoja no cuentan como registros
+	for count := 1; rows.Next(); count++ {
+		row, err := rows.Columns()
+		if err != nil {
+			return info, err
+		}
+		if len(row) > 0 {
+			info.Rows = count
+		}
+	}
+
+	if header != nil {
+		info.Headers = header
+	}
+	if mapping, err := s.DetectColumns(header, nil); err == nil {
+		info.Mapping = mapping
+		info.Matches = MappingMatches(mapping)
+	}
+	return info, nil
+}
+
+// MappingMatches indica si todos los campos de Contact se identificaron por su encabezado
+func MappingMatches(mapping []entities.ColumnMapping) bool {
+	if len(mapping) < len(entities.ContactFields) {
+		return false
+	}
+	for _, m := range mapping {
+		if m.Source == entities.MappingSourceDefault {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeReports combina los reportes de varias hojas en un solo reporte
+func MergeReports(reports ...*entities.ImportReport) *entities.ImportReport {
+	merged := &entities.ImportReport{SkippedRows: []entities.SkippedRow{}}
+	for _, report := range reports {
+		merged.RowsRead += report.RowsRead
+		merged.Imported += report.Imported
+		merged.Skipped += report.Skipped
+		merged.SkippedRows = append(merged.SkippedRows, report.SkippedRows...)
+	}
+	return merged
+}
+
+// ParseRows convierte filas de datos (sin header) en contactos según el mapeo de columnas.
+// firstRow es el número de fila en la hoja de la primera fila recibida; las filas
+// omitidas se reportan con ese número para poder corregirlas en el archivo original.
+// Cada contacto registra su origen (carga, archivo, hoja, fila y columna de cada campo).
+func (s *ImportService) ParseRows(rows [][]string, mapping []entities.ColumnMapping, origin entities.ContactSource, firstRow int) ([]*entities.Contact, *entities.ImportReport) {
+	requiredCells, firstCell := 0, -1
+	columns := make(map[string]string, len(mapping))
+	for _, m := range mapping {
+		if m.Index+1 > requiredCells {
+			requiredCells = m.Index + 1
+		}
+		if firstCell == -1 || m.Index < firstCell {
+			firstCell = m.Index
+		}
+		columns[m.Field] = m.Column
+	}
+
+	report := &entities.ImportReport{SkippedRows: []entities.SkippedRow{}}
+	var contacts []*entities.Contact
+	for i, row := range rows {
+		report.RowsRead++
+		rowNumber := firstRow + i
+
+		if reason, detail := checkRow(row, mapping, firstCell); reason != "" {
+			report.SkippedRows = append(report.SkippedRows, entities.SkippedRow{
+				Sheet:  origin.Sheet,
+				Row:    rowNumber,
+				Reason: reason,
+				Detail: detail,
+				Values: append([]string{}, row...),
+			})
+			continue
+		}
+		row = padRow(row, requiredCells)
+
+		source := origin
+		source.Row = rowNumber
+		source.Columns = columns
+
+		contact := &entities.Contact{Source: &source}
+		for _, m := range mapping {
+			value := strings.TrimSpace(row[m.Index])
+			switch m.Field {
+			case entities.FieldClientKey:
+				contact.ClientKey = value
+			case entities.FieldName:
+				contact.Name = value
+			case entities.FieldEmail:
+				contact.Email = value
+			case entities.FieldPhone:
+				contact.Phone = normalizeDisplayPhone(value)
+			}
+		}
+		enrichPhone(contact)
+
+		contacts = append(contacts, contact)
+	}
+
+	report.Imported = len(contacts)
+	report.Skipped = len(report.SkippedRows)
+	return contacts, report
+}
+
+// checkRow determina si una fila debe omitirse y por qué. Las hojas de cálculo omiten
+// las celdas vacías al final de la fila, así que una fila más corta que el mapeo se
+// importa con esas celdas vacías (y la validación marca los campos faltantes); solo se
+// omite si no llega a ninguna de las columnas asignadas.
+func checkRow(row []string, mapping []entities.ColumnMapping, firstCell int) (string, string) {
+	blank := true
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			blank = false
+			break
+		}
+	}
+	if blank {
+		return entities.SkipReasonBlankRow, "La fila está vacía"
+	}
+
+	if len(row) <= firstCell {
+		return entities.SkipReasonTooFewCells, fmt.Sprintf("La fila tiene %d celdas y no llega a la columna %s", len(row), columnName(firstCell))
+	}
+
+	for _, m := range mapping {
+		if m.Index < len(row) && !isParsableCell(row[m.Index]) {
+			return entities.SkipReasonUnparsableCell, fmt.Sprintf("No se pudo interpretar la celda %s (%s)", m.Column, m.Field)
+		}
+	}
+
+	return "", ""
+}
+
+// padRow completa con celdas vacías una fila más corta que el mapeo
+func padRow(row []string, cells int) []string {
+	if len(row) >= cells {
+		return row
+	}
+	padded := make([]string, cells)
+	copy(padded, row)
+	return padded
+}
+
+// excelErrorValues contiene los valores de error que Excel escribe en celdas con fórmulas inválidas
+var excelErrorValues = map[string]bool{
+	"#NULL!": true, "#DIV/0!": true, "#VALUE!": true, "#REF!": true,
+	"#NAME?": true, "#NUM!": true, "#N/A": true, "#GETTING_DATA": true,
+}
+
+// isParsableCell indica si el contenido de una celda puede interpretarse como texto
+func isParsableCell(value string) bool {
+	if !utf8.ValidString(value) || excelErrorValues[strings.TrimSpace(value)] {
+		return false
+	}
+
+	for _, char := range value {
+		if unicode.IsControl(char) && !unicode.IsSpace(char) {
+			return false
+		}
+	}
+	return true
+}
+
+// headerScore calcula qué tan parecido es un encabezado a los sinónimos de un campo
+func (s *ImportService) headerScore(field, header string) float64 {
+	normalized := normalizeText(header)
+	if normalized == "" {
+		return 0
+	}
+
+	headerTokens := strings.Fields(normalized)
+	best := 0.0
+	for _, synonym := range s.synonyms[field] {
+		var score float64
+		switch {
+		case normalized == synonym:
+			score = 1
+		case containsTokens(headerTokens, strings.Fields(synonym)):
+			ratio := float64(len(strings.Fields(synonym))) / float64(len(headerTokens))
+			score = 0.6 + 0.3*ratio
+		default:
+			if sim := similarity(normalized, synonym); sim >= 0.8 {
+				score = 0.9 * sim
+			}
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// resolveColumn busca la columna indicada por un encabezado o por una letra de columna
+func (s *ImportService) resolveColumn(header []string, target string) (int, bool) {
+	normalizedTarget := normalizeText(target)
+	for index, cell := range header {
+		if normalizedTarget != "" && normalizeText(cell) == normalizedTarget {
+			return index, true
+		}
+	}
+
+	return columnIndex(target)
+}
+
+func (s *ImportService) newMapping(field string, header []string, index int, score float64, source string) entities.ColumnMapping {
+	headerText := ""
+	if index < len(header) {
+		headerText = header[index]
+	}
+
+	return entities.ColumnMapping{
+		Field:  field,
+		Header: headerText,
+		Column: columnName(index),
+		Index:  index,
+		Score:  score,
+		Source: source,
+	}
+}
+
+// containsTokens indica si todos los tokens buscados aparecen en la lista de tokens
+func containsTokens(tokens, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, t := range tokens {
+			if t == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
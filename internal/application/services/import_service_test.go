@@ -0,0 +1,202 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+func TestDetectColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    []string
+		overrides map[string]string
+		// columns lista la columna esperada de cada campo en el orden de ContactFields
+		columns []string
+		sources []string
+		err     string
+	}{
+		{
+			name:    "encabezados en el orden por defecto",
+			header:  []string{"Clave", "Nombre", "Correo", "Teléfono"},
+			columns: []string{"A", "B", "C", "D"},
+			sources: []string{"detected", "detected", "detected", "detected"},
+		},
+		{
+			name:    "encabezados en otro orden y con acentos",
+			header:  []string{"Teléfono Contacto", "E-mail", "Número de Cliente", "Nombre Completo"},
+			columns: []string{"C", "D", "B", "A"},
+			sources: []string{"detected", "detected", "detected", "detected"},
+		},
+		{
+			name:    "encabezados desconocidos usan la posición por defecto",
+			header:  []string{"col1", "col2", "col3", "col4"},
+			columns: []string{"A", "B", "C", "D"},
+			sources: []string{"default", "default", "default", "default"},
+		},
+		{
+			name:      "mapeo explícito por encabezado y por letra",
+			header:    []string{"Clave", "Nombre", "Correo", "Teléfono", "Celular"},
+			overrides: map[string]string{"phone": "E", "email": "correo"},
+			columns:   []string{"A", "B", "C", "E"},
+			sources:   []string{"detected", "detected", "explicit", "explicit"},
+		},
+		{
+			name:      "campo desconocido en el mapeo",
+			header:    []string{"Clave", "Nombre", "Correo", "Teléfono"},
+			overrides: map[string]string{"fax": "A"},
+			err:       "campo desconocido",
+		},
+		{
+			name:      "columna inexistente en el mapeo",
+			header:    []string{"Clave", "Nombre", "Correo", "Teléfono"},
+			overrides: map[string]string{"phone": "Fax 2"},
+			err:       "no se encontró la columna",
+		},
+		{
+			name:      "columna asignada a dos campos",
+			header:    []string{"Clave", "Nombre", "Correo", "Teléfono"},
+			overrides: map[string]string{"name": "B", "email": "B"},
+			err:       "más de un campo",
+		},
+	}
+
+	service := NewImportService(nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := service.DetectColumns(tt.header, tt.overrides)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("DetectColumns() error = %v, se esperaba %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectColumns() error = %v", err)
+			}
+
+			if len(mapping) != len(entities.ContactFields) {
+				t.Fatalf("DetectColumns() asignó %d campos, se esperaban %d", len(mapping), len(entities.ContactFields))
+			}
+			for i, m := range mapping {
+				if m.Field != entities.ContactFields[i] {
+					t.Errorf("mapping[%d].Field = %s, se esperaba %s", i, m.Field, entities.ContactFields[i])
+				}
+				if m.Column != tt.columns[i] || m.Source != tt.sources[i] {
+					t.Errorf("%s: columna %s (%s), se esperaba %s (%s)", m.Field, m.Column, m.Source, tt.columns[i], tt.sources[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMappingMatches(t *testing.T) {
+	service := NewImportService(nil, nil, nil)
+	tests := []struct {
+		name   string
+		header []string
+		want   bool
+	}{
+		{name: "todos los campos por encabezado", header: []string{"Clave", "Nombre", "Correo", "Tel"}, want: true},
+		{name: "un campo por posición", header: []string{"Clave", "Nombre", "Correo", "Otro"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := service.DetectColumns(tt.header, nil)
+			if err != nil {
+				t.Fatalf("DetectColumns() error = %v", err)
+			}
+			if got := MappingMatches(mapping); got != tt.want {
+				t.Errorf("MappingMatches() = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRowsReport(t *testing.T) {
+	service := NewImportService(nil, nil, nil)
+	mapping, err := service.DetectColumns([]string{"Clave", "Nombre", "Correo", "Tel"}, nil)
+	if err != nil {
+		t.Fatalf("DetectColumns() error = %v", err)
+	}
+	// Con el mapeo desplazado a partir de la columna B una fila de una celda no llega a
+	// ninguna columna asignada
+	shifted, err := service.DetectColumns([]string{"", "Clave", "Nombre", "Correo", "Tel"}, nil)
+	if err != nil {
+		t.Fatalf("DetectColumns() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		mapping  []entities.ColumnMapping
+		rows     [][]string
+		imported int
+		// skipped lista la fila y el motivo de cada fila omitida
+		skipped []entities.SkippedRow
+	}{
+		{
+			name:     "filas completas",
+			mapping:  mapping,
+			rows:     [][]string{{"1", "Ana", "ana@gmail.com", "9611234567"}, {"2", "Beto", "beto@gmail.com", "9611234568"}},
+			imported: 2,
+		},
+		{
+			name:     "fila vacía",
+			mapping:  mapping,
+			rows:     [][]string{{"1", "Ana", "ana@gmail.com", "9611234567"}, {}, {" ", ""}},
+			imported: 1,
+			skipped:  []entities.SkippedRow{{Row: 3, Reason: entities.SkipReasonBlankRow}, {Row: 4, Reason: entities.SkipReasonBlankRow}},
+		},
+		{
+			name:     "fila corta se completa con celdas vacías",
+			mapping:  mapping,
+			rows:     [][]string{{"1", "Ana"}},
+			imported: 1,
+		},
+		{
+			name:     "fila que no llega a las columnas asignadas",
+			mapping:  shifted,
+			rows:     [][]string{{"x"}, {"", "1", "Ana", "ana@gmail.com", "9611234567"}},
+			imported: 1,
+			skipped:  []entities.SkippedRow{{Row: 2, Reason: entities.SkipReasonTooFewCells}},
+		},
+		{
+			name:     "celda con error de fórmula",
+			mapping:  mapping,
+			rows:     [][]string{{"1", "#REF!", "ana@gmail.com", "9611234567"}},
+			imported: 0,
+			skipped:  []entities.SkippedRow{{Row: 2, Reason: entities.SkipReasonUnparsableCell}},
+		},
+	}
+
+	origin := entities.ContactSource{UploadID: "carga", FileName: "contactos.xlsx", Sheet: "Hoja1"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts, report := service.ParseRows(tt.rows, tt.mapping, origin, 2)
+
+			if report.RowsRead != len(tt.rows) || report.Imported != tt.imported || report.Skipped != len(tt.skipped) {
+				t.Fatalf("reporte = %d leídas, %d importadas, %d omitidas; se esperaban %d, %d, %d",
+					report.RowsRead, report.Imported, report.Skipped, len(tt.rows), tt.imported, len(tt.skipped))
+			}
+			if len(contacts) != tt.imported {
+				t.Fatalf("ParseRows() retornó %d contactos, se esperaban %d", len(contacts), tt.imported)
+			}
+			for i, want := range tt.skipped {
+				got := report.SkippedRows[i]
+				if got.Row != want.Row || got.Reason != want.Reason || got.Sheet != origin.Sheet {
+					t.Errorf("SkippedRows[%d] = fila %d %s (%s), se esperaba fila %d %s", i, got.Row, got.Reason, got.Sheet, want.Row, want.Reason)
+				}
+				if got.Detail == "" {
+					t.Errorf("SkippedRows[%d] no explica por qué se omitió", i)
+				}
+			}
+			for _, contact := range contacts {
+				if contact.Source == nil || contact.Source.FileName != origin.FileName || contact.Source.Row < 2 {
+					t.Errorf("contacto %s sin origen válido: %+v", contact.ClientKey, contact.Source)
+				}
+			}
+		})
+	}
+}
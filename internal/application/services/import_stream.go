@@ -0,0 +1,617 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+	"analizador-backend/internal/domain/repositories"
+)
+
+// importBatchSize es el número de filas que se interpretan, validan y guardan juntas en
+// una carga por lotes
+const importBatchSize = 1000
+
+// errSheetWithoutRecords indica que una hoja no tiene filas de datos
+var errSheetWithoutRecords = errors.New("la hoja debe contener al menos un registro además del header")
+
+// Motivos por los que se omite una hoja en el modo de todas las hojas
+const (
+	reasonUnreadableRows  = "No se pudieron leer las filas"
+	reasonHeadersMismatch = "Los encabezados de la hoja no coinciden con los campos de contacto"
+)
+
+// UploadReadError indica que una carga no se pudo leer por un problema del archivo o de
+// sus opciones, a diferencia de los errores al guardar los contactos
+type UploadReadError struct {
+	Err error
+}
+
+func (e *UploadReadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UploadReadError) Unwrap() error {
+	return e.Err
+}
+
+// UploadProgress es el avance de una carga
+type UploadProgress struct {
+	RowsRead int
+	Imported int
+	Skipped  int
+	// TotalRows es el número de filas de las hojas que se importan, o cero si el archivo no
+	// permite conocerlo sin recorrerlo
+	TotalRows int
+	// Percent es el porcentaje del archivo procesado, estimado por las filas leídas o, si
+	// no se conoce el total, por los bytes leídos
+	Percent int
+	// Saving indica que se terminó de leer el archivo y se van a guardar los contactos en
+	// el dataset destino; cancelar el contexto después de recibirlo ya no detiene la carga
+	Saving bool
+}
+
+// ImportProgress recibe el avance de una carga
+type ImportProgress func(progress UploadProgress)
+
+// Upload lee un archivo y guarda sus contactos. Las hojas se recorren fila por fila y las
+// filas se guardan en lotes en un dataset provisional, de modo que la memoria usada no
+// depende del tamaño del archivo; en modo upsert cada lote se compara después con el
+// índice de claves del dataset destino. Al terminar, los contactos pasan juntos al dataset
+// destino, que nunca muestra una carga a medias. Cancelar el contexto antes de que se
+// guarden los contactos detiene la carga y descarta lo leído; progress, si no es nil,
+// recibe el avance después de cada lote y justo antes de guardar.
+func (s *ImportService) Upload(ctx context.Context, workbook readers.Workbook, fileName, datasetID, datasetName string, options entities.ImportOptions, progress ImportProgress) (*entities.ImportCommit, error) {
+	load, err := s.readUpload(ctx, workbook, fileName, datasetID, options, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := load.commit(datasetID, datasetName)
+	if err != nil {
+		load.discard()
+		return nil, err
+	}
+	return commit, nil
+}
+
+// CheckUpload revisa las opciones de una carga y que el archivo tenga las hojas
+// seleccionadas, sin leer sus filas
+func (s *ImportService) CheckUpload(workbook readers.Workbook, datasetID string, options entities.ImportOptions) error {
+	options, err := normalizeOptions(options)
+	if err != nil {
+		return &UploadReadError{Err: err}
+	}
+	if _, err := s.datasetService.ValidationProfile(datasetID, options.Profile); err != nil {
+		return &UploadReadError{Err: err}
+	}
+	if _, err := s.SelectSheets(workbook.SheetList(), options.Sheet); err != nil {
+		return &UploadReadError{Err: err}
+	}
+	return nil
+}
+
+// readUpload revisa las opciones de una carga y guarda las filas de las hojas seleccionadas
+// en un dataset provisional, leyendo, interpretando y guardando en lotes de importBatchSize.
+// Antes de leer las filas se revisan los headers de todas las hojas, de modo que el total
+// de filas del avance solo incluye las hojas que se importan. Si la lectura falla o se
+// cancela se descarta lo guardado.
+func (s *ImportService) readUpload(ctx context.Context, workbook readers.Workbook, fileName, datasetID string, options entities.ImportOptions, progress ImportProgress) (*batchLoad, error) {
+	options, err := normalizeOptions(options)
+	if err != nil {
+		return nil, &UploadReadError{Err: err}
+	}
+
+	profile, err := s.datasetService.ValidationProfile(datasetID, options.Profile)
+	if err != nil {
+		return nil, &UploadReadError{Err: err}
+	}
+	if _, err := s.contactService.validatorService.ForProfile(profile); err != nil {
+		return nil, &UploadReadError{Err: err}
+	}
+
+	allSheets := s.IsAllSheets(options.Sheet)
+	sheets, err := s.SelectSheets(workbook.SheetList(), options.Sheet)
+	if err != nil {
+		return nil, &UploadReadError{Err: err}
+	}
+
+	origin := s.NewUpload(fileName)
+	result := &entities.ImportResult{
+		UploadID:      origin.UploadID,
+		FileName:      fileName,
+		Format:        workbook.Format(),
+		Options:       options,
+		Sheets:        []*entities.SheetImport{},
+		SkippedSheets: []entities.SkippedSheet{},
+	}
+
+	var plans []*sheetPlan
+	for _, sheet := range sheets {
+		plan, err := s.planSheet(workbook, sheet, options.Mapping, allSheets)
+		var skipped *skippedSheetError
+		if errors.As(err, &skipped) && allSheets {
+			result.SkippedSheets = append(result.SkippedSheets, entities.SkippedSheet{Sheet: sheet, Reason: skipped.reason})
+			continue
+		}
+		if errors.As(err, &skipped) {
+			return nil, &UploadReadError{Err: skipped.err}
+		}
+		plans = append(plans, plan)
+	}
+
+	load := s.newBatchLoad(ctx, result, repositories.StagingDatasetPrefix+newID())
+	load.progress = progress
+	load.totalRows = countPlannedRows(workbook, plans)
+	load.sheetCount = len(plans)
+
+	var reports []*entities.ImportReport
+	load.report(nil, false)
+	for _, plan := range plans {
+		origin.Sheet = plan.sheet
+		sheetResult, err := load.importSheet(workbook, plan, origin)
+		load.sheetsDone++
+		var skipped *skippedSheetError
+		if errors.As(err, &skipped) && allSheets {
+			result.SkippedSheets = append(result.SkippedSheets, entities.SkippedSheet{Sheet: plan.sheet, Reason: skipped.reason})
+			continue
+		}
+		if err != nil {
+			load.discard()
+			if errors.As(err, &skipped) {
+				return nil, &UploadReadError{Err: skipped.err}
+			}
+			return nil, err
+		}
+
+		result.Sheets = append(result.Sheets, sheetResult)
+		reports = append(reports, sheetResult.Report)
+	}
+
+	if len(result.Sheets) == 0 {
+		load.discard()
+		return nil, &UploadReadError{Err: fmt.Errorf("ninguna hoja del archivo contiene contactos para importar")}
+	}
+	result.Report = MergeReports(reports...)
+	return load, nil
+}
+
+// skippedSheetError indica que una hoja no se puede importar antes de guardar alguna de
+// sus filas; en el modo de todas las hojas la hoja se omite con el motivo indicado
+type skippedSheetError struct {
+	reason string
+	err    error
+}
+
+func (e *skippedSheetError) Error() string {
+	return e.err.Error()
+}
+
+// sheetPlan representa una hoja que se va a importar con las columnas detectadas en su header
+type sheetPlan struct {
+	sheet   string
+	mapping []entities.ColumnMapping
+}
+
+// planSheet lee el header de una hoja y detecta sus columnas. Retorna un skippedSheetError
+// si la hoja no se puede importar.
+func (s *ImportService) planSheet(workbook readers.Workbook, sheet string, overrides map[string]string, requireMatch bool) (*sheetPlan, error) {
+	unreadable := &skippedSheetError{reason: reasonUnreadableRows, err: errors.New("no se pudieron leer las filas")}
+	rows, err := OpenRows(workbook, sheet)
+	if err != nil {
+		return nil, unreadable
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, &skippedSheetError{reason: errSheetWithoutRecords.Error(), err: errSheetWithoutRecords}
+	}
+	header, err := rows.Columns()
+	if err != nil {
+		return nil, unreadable
+	}
+
+	mapping, err := s.DetectColumns(header, overrides)
+	if err != nil {
+		return nil, &skippedSheetError{reason: err.Error(), err: err}
+	}
+	if requireMatch && !MappingMatches(mapping) {
+		return nil, &skippedSheetError{reason: reasonHeadersMismatch, err: errors.New("los encabezados de la hoja no coinciden con los campos de contacto")}
+	}
+	return &sheetPlan{sheet: sheet, mapping: mapping}, nil
+}
+
+// countPlannedRows suma las filas de las hojas que se van a importar si el libro puede
+// contarlas sin recorrerlas; retorna cero si alguna no se puede contar
+func countPlannedRows(workbook readers.Workbook, plans []*sheetPlan) int {
+	counter, ok := workbook.(readers.RowCounter)
+	if !ok {
+		return 0
+	}
+
+	total := 0
+	for _, plan := range plans {
+		count, ok := counter.RowCount(plan.sheet)
+		if !ok {
+			return 0
+		}
+		total += count
+	}
+	return total
+}
+
+// batchLoad guarda las filas de una carga en un dataset provisional y, al terminar, las
+// aplica al dataset destino
+type batchLoad struct {
+	ctx      context.Context
+	progress ImportProgress
+	service  *ImportService
+	options  entities.ImportOptions
+	result   *entities.ImportResult
+	// pendingID es el dataset provisional en que se guardan las filas hasta aplicar la carga
+	// y changesID el de las filas que un upsert inserta o actualiza
+	pendingID string
+	changesID string
+
+	dataset  *entities.Dataset
+	created  bool
+	applied  bool
+	rowsRead int
+	imported int
+	skipped  int
+
+	// totalRows, sheetCount y sheetsDone permiten estimar el avance de la carga
+	totalRows  int
+	sheetCount int
+	sheetsDone int
+}
+
+// newBatchLoad crea la carga de un resultado cuyas filas se guardan en pendingID
+func (s *ImportService) newBatchLoad(ctx context.Context, result *entities.ImportResult, pendingID string) *batchLoad {
+	return &batchLoad{
+		ctx:       ctx,
+		service:   s,
+		options:   result.Options,
+		result:    result,
+		pendingID: pendingID,
+		changesID: repositories.StagingDatasetPrefix + newID(),
+	}
+}
+
+// importSheet lee las filas de una hoja después del header y las guarda en lotes
+func (l *batchLoad) importSheet(workbook readers.Workbook, plan *sheetPlan, origin entities.ContactSource) (*entities.SheetImport, error) {
+	rows, err := OpenRows(workbook, plan.sheet)
+	if err != nil {
+		return nil, &skippedSheetError{reason: reasonUnreadableRows, err: errors.New("no se pudieron leer las filas")}
+	}
+	defer rows.Close()
+
+	// Omitir el header, que ya se revisó al planear la hoja
+	if !rows.Next() {
+		return nil, &skippedSheetError{reason: errSheetWithoutRecords.Error(), err: errSheetWithoutRecords}
+	}
+
+	report := &entities.ImportReport{SkippedRows: []entities.SkippedRow{}}
+	batch := make([][]string, 0, importBatchSize)
+	firstRow := 2
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+
+		contacts, batchReport := l.service.ParseRows(batch, plan.mapping, origin, firstRow)
+		firstRow += len(batch)
+		batch = batch[:0]
+
+		report.RowsRead += batchReport.RowsRead
+		report.Imported += batchReport.Imported
+		report.Skipped += batchReport.Skipped
+		report.SkippedRows = append(report.SkippedRows, batchReport.SkippedRows...)
+		if err := l.save(contacts); err != nil {
+			return err
+		}
+
+		l.rowsRead += batchReport.RowsRead
+		l.skipped += batchReport.Skipped
+		l.report(rows, false)
+		return nil
+	}
+
+	// Las filas vacías se guardan hasta encontrar una fila con datos para ignorar las
+	// del final de la hoja, igual que al leer la hoja completa
+	blankRows := 0
+	for rows.Next() {
+		row, err := rows.Columns()
+		if err != nil {
+			return nil, &UploadReadError{Err: fmt.Errorf("no se pudieron leer las filas de la hoja %s: %w", origin.Sheet, err)}
+		}
+		if len(row) == 0 {
+			blankRows++
+			continue
+		}
+
+		for ; blankRows > 0; blankRows-- {
+			batch = append(batch, []string{})
+			if len(batch) == importBatchSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		batch = append(batch, row)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if report.RowsRead == 0 {
+		return nil, &skippedSheetError{reason: errSheetWithoutRecords.Error(), err: errSheetWithoutRecords}
+	}
+
+	return &entities.SheetImport{
+		Sheet:   origin.Sheet,
+		Mapping: plan.mapping,
+		Report:  report,
+	}, nil
+}
+
+// report envía el avance de la carga. El porcentaje se estima por las filas leídas si se
+// conoce el total y, si no, por la parte leída de la hoja en curso. Antes de guardar los
+// contactos (saving) el archivo ya se leyó completo y el total son las filas leídas.
+func (l *batchLoad) report(rows readers.RowIterator, saving bool) {
+	if l.progress == nil {
+		return
+	}
+
+	percent := 100
+	if saving {
+		l.totalRows = l.rowsRead
+	} else {
+		var done float64
+		switch reader, ok := rows.(readers.ReadProgress); {
+		case l.totalRows > 0:
+			done = float64(l.rowsRead) / float64(l.totalRows)
+		case ok:
+			done = (float64(l.sheetsDone) + reader.ReadFraction()) / float64(l.sheetCount)
+		case l.sheetCount > 0:
+			done = float64(l.sheetsDone) / float64(l.sheetCount)
+		}
+		percent = min(int(done*100), 99)
+	}
+
+	l.progress(UploadProgress{
+		RowsRead:  l.rowsRead,
+		Imported:  l.imported,
+		Skipped:   l.skipped,
+		TotalRows: l.totalRows,
+		Percent:   percent,
+		Saving:    saving,
+	})
+}
+
+// save guarda un lote de contactos en el dataset provisional
+func (l *batchLoad) save(contacts []*entities.Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	if err := l.service.contactService.SaveContactsBatch(l.pendingID, contacts); err != nil {
+		return err
+	}
+	l.imported += len(contacts)
+	return nil
+}
+
+// commit aplica al dataset destino las filas guardadas en el dataset provisional. En modo
+// append pasan todas juntas; en modo upsert primero se decide qué hacer con cada lote
+// (planUpsert) y después los cambios se aplican en una sola operación. El dataset destino
+// se crea, si la carga no indica uno, justo antes de aplicar los contactos. Las
+// estadísticas de validación son las de todo el dataset después de la carga.
+func (l *batchLoad) commit(datasetID, datasetName string) (*entities.ImportCommit, error) {
+	contactService, datasetService := l.service.contactService, l.service.datasetService
+
+	outcome := &entities.ImportOutcome{Mode: l.options.Mode, Rows: []entities.RowOutcome{}}
+	if l.options.Mode == entities.ImportModeUpsert {
+		if err := l.planUpsert(datasetID, outcome); err != nil {
+			return nil, err
+		}
+	} else {
+		outcome.Inserted = l.imported
+	}
+
+	// Avisar que se van a guardar los contactos y revisar por última vez la cancelación
+	l.report(nil, true)
+	if err := l.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if datasetID == "" {
+		l.dataset, err = datasetService.CreateDataset(datasetName, l.result.FileName, l.options.Profile)
+		l.created = err == nil
+	} else {
+		l.dataset, err = datasetService.GetDataset(datasetID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := datasetService.TouchDataset(l.dataset.ID); err != nil {
+		return nil, err
+	}
+
+	if l.options.Mode == entities.ImportModeUpsert {
+		outcome.MarkedMissing, outcome.Removed, err = contactService.ApplyUpsert(l.changesID, l.pendingID, l.dataset.ID, l.options.Missing)
+	} else {
+		err = contactService.MoveContacts(l.pendingID, l.dataset.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.applied = true
+
+	// Las filas de un upsert sin cambios o en conflicto se quedan en el dataset provisional
+	contactService.DeleteAllContacts(l.pendingID)
+
+	profile, err := datasetService.ValidationProfile(l.dataset.ID, l.options.Profile)
+	if err != nil {
+		return nil, err
+	}
+	validations, err := contactService.ValidateAllContacts(l.dataset.ID, profile)
+	if err != nil {
+		return nil, err
+	}
+	stats := SummarizeResults(validations)
+
+	dataset, err := datasetService.GetDataset(l.dataset.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.ImportCommit{
+		Dataset:    dataset,
+		Result:     l.result,
+		Outcome:    outcome,
+		Validation: &stats,
+	}, nil
+}
+
+// planUpsert recorre en lotes las filas del dataset provisional y busca sus claves en el
+// índice de claves del dataset destino: las filas que insertan o actualizan un contacto
+// pasan al dataset de cambios y las filas sin cambios o en conflicto se quedan en el
+// provisional, donde sus claves siguen contando como presentes para la política de
+// contactos ausentes. Las filas sin clave, con claves repetidas en el archivo o que
+// coinciden con varios contactos del dataset se reportan como conflicto y no se aplican.
+// Una fila solo pasa al dataset de cambios si su clave aparece una vez en el archivo, así
+// que las claves que quedan en el provisional cuentan bien las repeticiones de los lotes
+// siguientes.
+func (l *batchLoad) planUpsert(datasetID string, outcome *entities.ImportOutcome) error {
+	contactService := l.service.contactService
+	afterID := 0
+	for {
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := contactService.ContactsPage(l.pendingID, afterID, importBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		afterID = rows[len(rows)-1].ID
+
+		keys := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if row.ClientKey != "" {
+				keys = append(keys, row.ClientKey)
+			}
+		}
+		fileKeys, err := contactService.ContactsByClientKey(l.pendingID, keys)
+		if err != nil {
+			return err
+		}
+		current := make(map[string][]*entities.Contact)
+		if datasetID != "" {
+			if current, err = contactService.ContactsByClientKey(datasetID, keys); err != nil {
+				return err
+			}
+		}
+
+		var changes []*entities.Contact
+		for _, row := range rows {
+			key := row.ClientKey
+			matches := current[key]
+			switch {
+			case key == "":
+				outcome.Rows = append(outcome.Rows, conflictRow(row, "La fila no tiene clave cliente"))
+			case len(fileKeys[key]) > 1:
+				outcome.Rows = append(outcome.Rows, conflictRow(row, fmt.Sprintf("La clave cliente aparece %d veces en el archivo", len(fileKeys[key]))))
+			case len(matches) > 1:
+				outcome.Rows = append(outcome.Rows, conflictRow(row, fmt.Sprintf("La clave cliente corresponde a %d contactos del dataset", len(matches))))
+			case len(matches) == 1 && sameContactData(matches[0], row) && matches[0].Status == "":
+				outcome.Unchanged++
+			case len(matches) == 1:
+				outcome.Updated++
+				changes = append(changes, row)
+			default:
+				outcome.Inserted++
+				changes = append(changes, row)
+			}
+		}
+		outcome.Conflicts = len(outcome.Rows)
+
+		if err := contactService.MoveContactsTo(l.changesID, changes); err != nil {
+			return err
+		}
+	}
+}
+
+// conflictRow crea el resultado de una fila de un upsert que no se aplicó
+func conflictRow(contact *entities.Contact, detail string) entities.RowOutcome {
+	row := newRowOutcome(contact)
+	row.Outcome = entities.RowOutcomeConflict
+	row.Detail = detail
+	return row
+}
+
+// discard descarta las filas de los datasets provisionales y, si la carga no se aplicó,
+// el dataset que creó
+func (l *batchLoad) discard() {
+	contactService := l.service.contactService
+	contactService.DeleteAllContacts(l.pendingID)
+	contactService.DeleteAllContacts(l.changesID)
+	if l.created && !l.applied {
+		l.service.datasetService.DeleteDataset(l.dataset.ID)
+	}
+}
+
+// restore deja una carga que no se pudo aplicar como estaba antes de intentarlo: regresa al
+// dataset provisional las filas que pasaron al de cambios y elimina el dataset que creó
+func (l *batchLoad) restore() {
+	l.service.contactService.MoveContacts(l.changesID, l.pendingID)
+	if l.created && !l.applied {
+		l.service.datasetService.DeleteDataset(l.dataset.ID)
+	}
+}
+
+// OpenRows abre un iterador sobre las filas de una hoja. Los libros que no pueden leer
+// fila por fila se recorren sobre sus filas ya cargadas.
+func OpenRows(workbook readers.Workbook, sheet string) (readers.RowIterator, error) {
+	if streamer, ok := workbook.(readers.RowStreamer); ok {
+		return streamer.StreamRows(sheet)
+	}
+
+	rows, err := workbook.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &sliceRows{rows: rows, index: -1}, nil
+}
+
+// sliceRows recorre filas que ya están en memoria
+type sliceRows struct {
+	rows  [][]string
+	index int
+}
+
+func (r *sliceRows) Next() bool {
+	r.index++
+	return r.index < len(r.rows)
+}
+
+func (r *sliceRows) Columns() ([]string, error) {
+	return r.rows[r.index], nil
+}
+
+func (r *sliceRows) Close() error {
+	return nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+	"analizador-backend/internal/infrastructure/repositories"
+)
+
+// testImport agrupa los servicios de una carga sobre repositorios en memoria
+type testImport struct {
+	contacts *ContactService
+	datasets *DatasetService
+	imports  *ImportService
+}
+
+func newTestImport(uniqueClientKey bool) *testImport {
+	validator := NewValidatorService()
+	contacts := NewContactService(repositories.NewInMemoryContactRepository(repositories.ContactRepositoryOptions{
+		UniqueClientKey: uniqueClientKey,
+	}), validator)
+	datasets := NewDatasetService(repositories.NewInMemoryDatasetRepository(), contacts, repositories.NewInMemoryMergeRecordRepository(), validator)
+	imports := NewImportService(contacts, datasets, repositories.NewInMemoryStagedImportRepository())
+	return &testImport{contacts: contacts, datasets: datasets, imports: imports}
+}
+
+// rowsWorkbook es un libro de una hoja con filas en memoria
+type rowsWorkbook [][]string
+
+func (w rowsWorkbook) Format() entities.FileFormat { return entities.FileFormat{Name: "csv"} }
+func (w rowsWorkbook) SheetList() []string         { return []string{"contactos"} }
+func (w rowsWorkbook) Rows(sheet string) ([][]string, error) {
+	return w, nil
+}
+func (w rowsWorkbook) Close() error { return nil }
+
+var _ readers.Workbook = rowsWorkbook(nil)
+
+// contactRows arma un libro con el header de contactos y una fila por clave y nombre
+func contactRows(keysAndNames ...string) rowsWorkbook {
+	rows := rowsWorkbook{{"Clave", "Nombre", "Correo", "Telefono"}}
+	for i := 0; i+1 < len(keysAndNames); i += 2 {
+		rows = append(rows, []string{keysAndNames[i], keysAndNames[i+1], "contacto@gmail.com", "9611234567"})
+	}
+	return rows
+}
+
+// datasetState obtiene nombre y estado de cada contacto de un dataset por clave cliente
+func datasetState(t *testing.T, contacts *ContactService, datasetID string) map[string]string {
+	t.Helper()
+	all, err := contacts.GetAllContacts(datasetID)
+	if err != nil {
+		t.Fatalf("GetAllContacts() error = %v", err)
+	}
+	state := make(map[string]string, len(all))
+	for _, contact := range all {
+		state[contact.ClientKey] = contact.Name + contact.Status
+	}
+	return state
+}
+
+func TestUploadUpsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing rowsWorkbook
+		file     rowsWorkbook
+		missing  string
+		outcome  entities.ImportOutcome
+		// state es el nombre, seguido del estado, de cada contacto del dataset después de la carga
+		state map[string]string
+	}{
+		{
+			name:     "inserta, actualiza y conserva los ausentes",
+			existing: contactRows("1", "Ana", "2", "Beto", "3", "Carla"),
+			file:     contactRows("1", "Ana", "2", "Beto Ruiz", "4", "Dora"),
+			outcome:  entities.ImportOutcome{Inserted: 1, Updated: 1, Unchanged: 1},
+			state:    map[string]string{"1": "Ana", "2": "Beto Ruiz", "3": "Carla", "4": "Dora"},
+		},
+		{
+			name:     "marca los ausentes",
+			existing: contactRows("1", "Ana", "2", "Beto"),
+			file:     contactRows("1", "Ana"),
+			missing:  entities.MissingPolicyMark,
+			outcome:  entities.ImportOutcome{Unchanged: 1, MarkedMissing: 1},
+			state:    map[string]string{"1": "Ana", "2": "Beto" + entities.ContactStatusMissing},
+		},
+		{
+			name:     "elimina los ausentes",
+			existing: contactRows("1", "Ana", "2", "Beto"),
+			file:     contactRows("1", "Ana"),
+			missing:  entities.MissingPolicyRemove,
+			outcome:  entities.ImportOutcome{Unchanged: 1, Removed: 1},
+			state:    map[string]string{"1": "Ana"},
+		},
+		{
+			name:     "claves repetidas o vacías son conflictos y cuentan como presentes",
+			existing: contactRows("1", "Ana", "2", "Beto"),
+			file:     contactRows("2", "Beto A", "2", "Beto B", "", "Sin clave"),
+			missing:  entities.MissingPolicyRemove,
+			outcome:  entities.ImportOutcome{Conflicts: 3, Removed: 1},
+			state:    map[string]string{"2": "Beto"},
+		},
+		{
+			name:     "clave de varios contactos del dataset",
+			existing: contactRows("1", "Ana", "1", "Ana 2"),
+			file:     contactRows("1", "Ana 3"),
+			outcome:  entities.ImportOutcome{Conflicts: 1},
+			state:    map[string]string{"1": "Ana 2"},
+		},
+		{
+			name:    "dataset nuevo",
+			file:    contactRows("1", "Ana", "2", "Beto"),
+			outcome: entities.ImportOutcome{Inserted: 2},
+			state:   map[string]string{"1": "Ana", "2": "Beto"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestImport(false)
+			datasetID := ""
+			if tt.existing != nil {
+				commit, err := env.imports.Upload(context.Background(), tt.existing, "base.csv", "", "base", entities.ImportOptions{}, nil)
+				if err != nil {
+					t.Fatalf("Upload() de los contactos existentes error = %v", err)
+				}
+				datasetID = commit.Dataset.ID
+			}
+
+			options := entities.ImportOptions{Mode: entities.ImportModeUpsert, Missing: tt.missing}
+			commit, err := env.imports.Upload(context.Background(), tt.file, "carga.csv", datasetID, "carga", options, nil)
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+
+			outcome := commit.Outcome
+			if outcome.Inserted != tt.outcome.Inserted || outcome.Updated != tt.outcome.Updated ||
+				outcome.Unchanged != tt.outcome.Unchanged || outcome.Conflicts != tt.outcome.Conflicts ||
+				outcome.MarkedMissing != tt.outcome.MarkedMissing || outcome.Removed != tt.outcome.Removed {
+				t.Errorf("Outcome = %+v, se esperaba %+v", *outcome, tt.outcome)
+			}
+			if len(outcome.Rows) != tt.outcome.Conflicts {
+				t.Errorf("Outcome.Rows tiene %d filas, se esperaban solo los %d conflictos", len(outcome.Rows), tt.outcome.Conflicts)
+			}
+
+			state := datasetState(t, env.contacts, commit.Dataset.ID)
+			if fmt.Sprint(state) != fmt.Sprint(tt.state) {
+				t.Errorf("dataset = %v, se esperaba %v", state, tt.state)
+			}
+			count, _ := env.contacts.CountContacts(commit.Dataset.ID)
+			if commit.Validation == nil || commit.Validation.Total != count {
+				t.Errorf("Validation = %+v, se esperaban las estadísticas de los %d contactos del dataset", commit.Validation, count)
+			}
+		})
+	}
+}
+
+func TestUploadUpsertAcrossBatches(t *testing.T) {
+	// Una clave repetida en lotes distintos del archivo es conflicto en ambos
+	var keysAndNames []string
+	for i := 0; i < importBatchSize+10; i++ {
+		keysAndNames = append(keysAndNames, fmt.Sprintf("%05d", i), "Contacto")
+	}
+	keysAndNames = append(keysAndNames, "00001", "Repetido")
+
+	env := newTestImport(false)
+	options := entities.ImportOptions{Mode: entities.ImportModeUpsert}
+	commit, err := env.imports.Upload(context.Background(), contactRows(keysAndNames...), "carga.csv", "", "carga", options, nil)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if commit.Outcome.Inserted != importBatchSize+9 || commit.Outcome.Conflicts != 2 {
+		t.Errorf("Outcome = %d insertados y %d conflictos, se esperaban %d y 2", commit.Outcome.Inserted, commit.Outcome.Conflicts, importBatchSize+9)
+	}
+	if count, _ := env.contacts.CountContacts(commit.Dataset.ID); count != importBatchSize+9 {
+		t.Errorf("el dataset tiene %d contactos, se esperaban %d", count, importBatchSize+9)
+	}
+}
+
+func TestUploadCancelledDiscardsRows(t *testing.T) {
+	env := newTestImport(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	options := entities.ImportOptions{Mode: entities.ImportModeUpsert}
+	if _, err := env.imports.Upload(ctx, contactRows("1", "Ana"), "carga.csv", "", "carga", options, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Upload() error = %v, se esperaba context.Canceled", err)
+	}
+	if datasets, _ := env.datasets.GetAllDatasets(); len(datasets) != 0 {
+		t.Errorf("la carga cancelada dejó %d datasets", len(datasets))
+	}
+}
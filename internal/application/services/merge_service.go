@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type MergeService struct {
+	contactService   *ContactService
+	validatorService *ValidatorService
+	mergeRecordRepo  repositories.MergeRecordRepository
+}
+
+// NewMergeService crea una nueva instancia del servicio de fusión de contactos
+func NewMergeService(contactService *ContactService, validatorService *ValidatorService, mergeRecordRepo repositories.MergeRecordRepository) *MergeService {
+	return &MergeService{
+		contactService:   contactService,
+		validatorService: validatorService,
+		mergeRecordRepo:  mergeRecordRepo,
+	}
+}
+
+// MergeContacts fusiona varios contactos del dataset en el sobreviviente. El valor de cada
+// campo se toma del contacto indicado o según una regla (sobreviviente, más reciente, más
+// largo o primero válido); luego se guardan los cambios, se eliminan los demás contactos
+// y se registra la fusión. Si algún paso falla se restaura el sobreviviente y los
+// contactos eliminados.
+func (s *MergeService) MergeContacts(datasetID, profile string, request entities.MergeRequest) (*entities.MergeRecord, error) {
+	validator, err := s.validatorService.ForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, survivor, err := s.loadMergeContacts(datasetID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRule := request.DefaultRule
+	if defaultRule == "" {
+		defaultRule = entities.MergeRuleSurvivor
+	}
+
+	for field := range request.Fields {
+		if !containsString(entities.ContactFields, field) {
+			return nil, fmt.Errorf("campo desconocido: %s", field)
+		}
+	}
+
+	original := *survivor
+	merged := *survivor
+	record := &entities.MergeRecord{
+		ID:         newID(),
+		DatasetID:  datasetID,
+		SurvivorID: survivor.ID,
+	}
+
+	for _, field := range entities.ContactFields {
+		fieldRule, hasRule := request.Fields[field]
+		rule := defaultRule
+		if hasRule {
+			rule = fieldRule.Rule
+			if fieldRule.SourceID != 0 {
+				rule = entities.MergeRuleSource
+			}
+		}
+
+		source, err := chooseSource(validator, field, rule, fieldRule.SourceID, contacts, survivor)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := contactField(survivor, field)
+		value := contactField(source, field)
+		setContactField(&merged, field, value)
+		record.Fields = append(record.Fields, entities.MergedField{
+			Field:         field,
+			Rule:          rule,
+			SourceID:      source.ID,
+			Value:         value,
+			PreviousValue: previous,
+		})
+	}
+
+	// Los demás contactos se eliminan antes de actualizar el sobreviviente para que la
+	// restricción de clave única no rechace una clave tomada de ellos
+	for _, contact := range contacts {
+		if contact.ID == survivor.ID {
+			continue
+		}
+		if err := s.contactService.DeleteContact(datasetID, contact.ID); err != nil {
+			return nil, errors.Join(err, s.restoreContacts(record.Merged))
+		}
+		record.MergedIDs = append(record.MergedIDs, contact.ID)
+		record.Merged = append(record.Merged, *contact)
+	}
+
+	if err := s.contactService.UpdateContact(datasetID, &merged); err != nil {
+		return nil, errors.Join(err, s.restoreContacts(record.Merged))
+	}
+
+	record.Result = merged
+	if err := s.mergeRecordRepo.Save(record); err != nil {
+		// El sobreviviente se restaura primero porque pudo tomar la clave de un eliminado
+		restoreErr := s.contactService.UpdateContact(datasetID, &original)
+		if restoreErr != nil {
+			restoreErr = fmt.Errorf("no se pudo restaurar el contacto %d: %w", original.ID, restoreErr)
+		}
+		return nil, errors.Join(err, restoreErr, s.restoreContacts(record.Merged))
+	}
+	return record, nil
+}
+
+// GetMergeRecords obtiene el historial de fusiones de un dataset
+func (s *MergeService) GetMergeRecords(datasetID string) ([]*entities.MergeRecord, error) {
+	return s.mergeRecordRepo.FindAll(datasetID)
+}
+
+// restoreContacts vuelve a guardar los contactos eliminados de una fusión que no se completó
+// y devuelve juntos los errores de los que no se pudieron restaurar
+func (s *MergeService) restoreContacts(contacts []entities.Contact) error {
+	var errs []error
+	for i := range contacts {
+		contact := contacts[i]
+		if err := s.contactService.RestoreContact(&contact); err != nil {
+			errs = append(errs, fmt.Errorf("no se pudo restaurar el contacto %d: %w", contact.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadMergeContacts obtiene los contactos a fusionar en el orden solicitado y el sobreviviente
+func (s *MergeService) loadMergeContacts(datasetID string, request entities.MergeRequest) ([]*entities.Contact, *entities.Contact, error) {
+	seen := make(map[int]bool)
+	var contacts []*entities.Contact
+	var survivor *entities.Contact
+	for _, id := range request.ContactIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		contact, err := s.contactService.GetContact(datasetID, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("contacto %d no encontrado", id)
+		}
+		contacts = append(contacts, contact)
+		if id == request.SurvivorID {
+			survivor = contact
+		}
+	}
+
+	if len(contacts) < 2 {
+		return nil, nil, fmt.Errorf("se requieren al menos dos contactos para fusionar")
+	}
+	if survivor == nil {
+		return nil, nil, fmt.Errorf("el sobreviviente debe estar entre los contactos a fusionar")
+	}
+	return contacts, survivor, nil
+}
+
+// chooseSource elige el contacto del que se toma el valor de un campo según la regla
+func chooseSource(validator *ValidatorService, field, rule string, sourceID int, contacts []*entities.Contact, survivor *entities.Contact) (*entities.Contact, error) {
+	switch rule {
+	case entities.MergeRuleSurvivor:
+		return survivor, nil
+	case entities.MergeRuleSource:
+		for _, contact := range contacts {
+			if contact.ID == sourceID {
+				return contact, nil
+			}
+		}
+		return nil, fmt.Errorf("el contacto %d no está entre los contactos a fusionar", sourceID)
+	case entities.MergeRuleMostRecent:
+		var chosen *entities.Contact
+		for _, contact := range contacts {
+			if contactField(contact, field) == "" {
+				continue
+			}
+			if chosen == nil || contact.UpdatedAt.After(chosen.UpdatedAt) {
+				chosen = contact
+			}
+		}
+		if chosen == nil {
+			return survivor, nil
+		}
+		return chosen, nil
+	case entities.MergeRuleLongest:
+		chosen := survivor
+		for _, contact := range contacts {
+			if len([]rune(contactField(contact, field))) > len([]rune(contactField(chosen, field))) {
+				chosen = contact
+			}
+		}
+		return chosen, nil
+	case entities.MergeRuleFirstValid:
+		for _, contact := range contacts {
+			if !hasErrors(validator.ValidateField(field, contactField(contact, field))) {
+				return contact, nil
+			}
+		}
+		return survivor, nil
+	}
+
+	return nil, fmt.Errorf("regla de fusión no válida para %s: %s", field, rule)
+}
+
+// contactField obtiene el valor de un campo de contacto por nombre
+func contactField(contact *entities.Contact, field string) string {
+	switch field {
+	case entities.FieldClientKey:
+		return contact.ClientKey
+	case entities.FieldName:
+		return contact.Name
+	case entities.FieldEmail:
+		return contact.Email
+	case entities.FieldPhone:
+		return contact.Phone
+	}
+	return ""
+}
+
+// setContactField asigna el valor de un campo de contacto por nombre
+func setContactField(contact *entities.Contact, field, value string) {
+	switch field {
+	case entities.FieldClientKey:
+		contact.ClientKey = value
+	case entities.FieldName:
+		contact.Name = value
+	case entities.FieldEmail:
+		contact.Email = value
+	case entities.FieldPhone:
+		contact.Phone = value
+	}
+}
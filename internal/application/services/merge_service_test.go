@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/infrastructure/repositories"
+)
+
+// failingMergeRecords es un repositorio de registros de fusión que no puede guardar
+type failingMergeRecords struct{}
+
+func (failingMergeRecords) Save(record *entities.MergeRecord) error {
+	return errors.New("sin espacio")
+}
+func (failingMergeRecords) FindAll(datasetID string) ([]*entities.MergeRecord, error) {
+	return nil, nil
+}
+func (failingMergeRecords) DeleteByDataset(datasetID string) error { return nil }
+
+func TestMergeContacts(t *testing.T) {
+	tests := []struct {
+		name string
+		// ids son las posiciones de los contactos a fusionar y survivor la del sobreviviente
+		ids      []int
+		survivor int
+		rule     string
+		// rules indica la regla de cada campo y sources la posición de su contacto de origen
+		rules   map[string]string
+		sources map[string]int
+		// touched son las posiciones de los contactos que se actualizan después de guardarlos
+		touched []int
+		// from es la posición del contacto del que se toma cada campo; los demás son del sobreviviente
+		from map[string]int
+		err  string
+	}{
+		{
+			name:     "el sobreviviente conserva sus valores",
+			ids:      []int{0, 1, 2},
+			survivor: 0,
+		},
+		{
+			name:     "campo tomado de otro contacto, incluida su clave única",
+			ids:      []int{0, 1},
+			survivor: 0,
+			sources:  map[string]int{entities.FieldClientKey: 1, entities.FieldName: 1},
+			from:     map[string]int{entities.FieldClientKey: 1, entities.FieldName: 1},
+		},
+		{
+			name:     "valor más largo",
+			ids:      []int{0, 1, 2},
+			survivor: 0,
+			rule:     entities.MergeRuleLongest,
+			from:     map[string]int{entities.FieldName: 1, entities.FieldEmail: 2},
+		},
+		{
+			name:     "primer valor válido en el orden solicitado",
+			ids:      []int{1, 0, 2},
+			survivor: 1,
+			rules:    map[string]string{entities.FieldEmail: entities.MergeRuleFirstValid},
+			from:     map[string]int{entities.FieldEmail: 0},
+		},
+		{
+			name:     "valores más recientes",
+			ids:      []int{0, 1, 2},
+			survivor: 0,
+			rule:     entities.MergeRuleMostRecent,
+			touched:  []int{2},
+			from:     map[string]int{entities.FieldClientKey: 2, entities.FieldName: 2, entities.FieldEmail: 2, entities.FieldPhone: 2},
+		},
+		{
+			name:     "campo desconocido",
+			ids:      []int{0, 1},
+			survivor: 0,
+			rules:    map[string]string{"fax": entities.MergeRuleSurvivor},
+			err:      "campo desconocido",
+		},
+		{
+			name:     "regla desconocida",
+			ids:      []int{0, 1},
+			survivor: 0,
+			rule:     "al_azar",
+			err:      "regla de fusión no válida",
+		},
+		{
+			name:     "origen fuera de la fusión",
+			ids:      []int{0, 1},
+			survivor: 0,
+			sources:  map[string]int{entities.FieldName: 2},
+			err:      "no está entre los contactos a fusionar",
+		},
+		{
+			name:     "un solo contacto",
+			ids:      []int{0, 0},
+			survivor: 0,
+			err:      "al menos dos contactos",
+		},
+		{
+			name:     "sobreviviente fuera de la fusión",
+			ids:      []int{0, 1},
+			survivor: 2,
+			err:      "el sobreviviente debe estar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestImport(true)
+			contacts := []*entities.Contact{
+				{ClientKey: "1", Name: "Ana", Email: "ana@gmail.com", Phone: "9611234567"},
+				{ClientKey: "2", Name: "Ana María Ruiz", Email: "correo-invalido"},
+				{ClientKey: "3", Name: "A. Ruiz", Email: "ana.ruiz@gmail.com", Phone: "9617654321"},
+			}
+			if err := env.contacts.SaveContactsBatch("d", contacts); err != nil {
+				t.Fatalf("SaveContactsBatch() error = %v", err)
+			}
+			for _, position := range tt.touched {
+				time.Sleep(time.Millisecond)
+				touched := *contacts[position]
+				if err := env.contacts.UpdateContact("d", &touched); err != nil {
+					t.Fatalf("UpdateContact() error = %v", err)
+				}
+			}
+			originals := make([]entities.Contact, len(contacts))
+			for i, contact := range contacts {
+				stored, _ := env.contacts.GetContact("d", contact.ID)
+				originals[i] = *stored
+			}
+
+			request := entities.MergeRequest{SurvivorID: contacts[tt.survivor].ID, DefaultRule: tt.rule}
+			for _, position := range tt.ids {
+				request.ContactIDs = append(request.ContactIDs, contacts[position].ID)
+			}
+			request.Fields = make(map[string]entities.FieldMergeRule)
+			for field, rule := range tt.rules {
+				request.Fields[field] = entities.FieldMergeRule{Rule: rule}
+			}
+			for field, position := range tt.sources {
+				request.Fields[field] = entities.FieldMergeRule{SourceID: contacts[position].ID}
+			}
+
+			records := repositories.NewInMemoryMergeRecordRepository()
+			record, err := NewMergeService(env.contacts, NewValidatorService(), records).MergeContacts("d", "", request)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("MergeContacts() error = %v, se esperaba %q", err, tt.err)
+				}
+				if count, _ := env.contacts.CountContacts("d"); count != len(contacts) {
+					t.Errorf("una fusión rechazada dejó %d contactos, se esperaban %d", count, len(contacts))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeContacts() error = %v", err)
+			}
+
+			survivor := &originals[tt.survivor]
+			for _, field := range entities.ContactFields {
+				source := survivor
+				if position, exists := tt.from[field]; exists {
+					source = &originals[position]
+				}
+				if got, want := contactField(&record.Result, field), contactField(source, field); got != want {
+					t.Errorf("%s = %q, se esperaba %q", field, got, want)
+				}
+			}
+
+			stored, err := env.contacts.GetContact("d", survivor.ID)
+			if err != nil || stored.Name != record.Result.Name || stored.ClientKey != record.Result.ClientKey {
+				t.Errorf("el sobreviviente guardado = %+v, se esperaba %+v", stored, record.Result)
+			}
+			if count, _ := env.contacts.CountContacts("d"); count != len(contacts)-len(record.MergedIDs) || len(record.MergedIDs) != len(tt.ids)-1 {
+				t.Errorf("quedaron %d contactos tras fusionar %v", count, record.MergedIDs)
+			}
+			if saved, _ := records.FindAll("d"); len(saved) != 1 || saved[0].ID != record.ID {
+				t.Errorf("registros de fusión = %d, se esperaba el registro de la fusión", len(saved))
+			}
+		})
+	}
+}
+
+func TestMergeContactsRestoresOnFailure(t *testing.T) {
+	env := newTestImport(true)
+	contacts := []*entities.Contact{
+		{ClientKey: "1", Name: "Ana", Email: "ana@gmail.com"},
+		{ClientKey: "2", Name: "Ana Ruiz", Email: "ana.ruiz@gmail.com"},
+	}
+	if err := env.contacts.SaveContactsBatch("d", contacts); err != nil {
+		t.Fatalf("SaveContactsBatch() error = %v", err)
+	}
+
+	service := NewMergeService(env.contacts, NewValidatorService(), failingMergeRecords{})
+	request := entities.MergeRequest{
+		ContactIDs:  []int{contacts[0].ID, contacts[1].ID},
+		SurvivorID:  contacts[0].ID,
+		DefaultRule: entities.MergeRuleLongest,
+	}
+	if _, err := service.MergeContacts("d", "", request); err == nil {
+		t.Fatalf("MergeContacts() no retornó el error del registro")
+	}
+
+	state := datasetState(t, env.contacts, "d")
+	if len(state) != 2 || state["1"] != "Ana" || state["2"] != "Ana Ruiz" {
+		t.Errorf("dataset = %v, se esperaban los contactos originales", state)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// numberingPlanCSV contiene las ladas del plan de numeración nacional con su estado y
+// ciudad principal (lada,estado,ciudad_principal). No incluye la lista de municipios que
+// cubre cada lada.
+//
+//go:embed data/numbering_plan_mx.csv
+var numberingPlanCSV string
+
+// mexicanNumberingPlan relaciona cada lada de 2 o 3 dígitos con su ubicación
+var mexicanNumberingPlan = loadNumberingPlan(numberingPlanCSV)
+
+// nationalPrefixes son los prefijos que pueden anteponerse a un número nacional: código
+// de país (+52, +521 para celulares), prefijo de celular (044, 045) y larga distancia (01)
+var nationalPrefixes = []string{"521", "52", "044", "045", "01"}
+
+// loadNumberingPlan lee el plan de numeración embebido
+func loadNumberingPlan(data string) map[string]entities.PhoneLocation {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(err)
+	}
+
+	plan := make(map[string]entities.PhoneLocation, len(records))
+	for _, record := range records[1:] {
+		plan[record[0]] = entities.PhoneLocation{
+			AreaCode: record[0],
+			State:    record[1],
+			MainCity: record[2],
+		}
+	}
+	return plan
+}
+
+// lookupAreaCode obtiene la ubicación de un número nacional de 10 dígitos según su lada.
+// Las ladas de 2 dígitos (55, 56, 33, 81) no son prefijo de ninguna lada de 3 dígitos.
+func lookupAreaCode(digits string) (entities.PhoneLocation, bool) {
+	for _, length := range []int{2, 3} {
+		if len(digits) < length {
+			break
+		}
+		if location, exists := mexicanNumberingPlan[digits[:length]]; exists {
+			return location, true
+		}
+	}
+	return entities.PhoneLocation{}, false
+}
+
+// isRealSubscriber indica si el número local que sigue a la lada puede existir: no repite
+// un mismo dígito ni es una secuencia consecutiva
+func isRealSubscriber(digits string, areaCode string) bool {
+	subscriber := digits[len(areaCode):]
+	if subscriber == "" {
+		return false
+	}
+
+	repeated, ascending, descending := true, true, true
+	for i := 1; i < len(subscriber); i++ {
+		if subscriber[i] != subscriber[0] {
+			repeated = false
+		}
+		if subscriber[i] != subscriber[i-1]+1 {
+			ascending = false
+		}
+		if subscriber[i] != subscriber[i-1]-1 {
+			descending = false
+		}
+	}
+	return !repeated && !ascending && !descending
+}
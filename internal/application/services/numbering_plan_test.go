@@ -0,0 +1,111 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+func TestNumberingPlan(t *testing.T) {
+	if len(mexicanNumberingPlan) < 300 {
+		t.Fatalf("el plan de numeración tiene %d ladas", len(mexicanNumberingPlan))
+	}
+	for code, location := range mexicanNumberingPlan {
+		if len(code) < 2 || len(code) > 3 || !isDigits(code) || location.AreaCode != code || location.State == "" {
+			t.Errorf("lada %q mal formada: %+v", code, location)
+		}
+		// Una lada de 2 dígitos no puede ser prefijo de una de 3 porque se busca primero
+		if len(code) == 3 {
+			if _, exists := mexicanNumberingPlan[code[:2]]; exists {
+				t.Errorf("la lada %s empieza con la lada de 2 dígitos %s", code, code[:2])
+			}
+		}
+	}
+}
+
+func TestLookupAreaCode(t *testing.T) {
+	tests := []struct {
+		digits   string
+		location entities.PhoneLocation
+		exists   bool
+	}{
+		{digits: "5583527411", location: entities.PhoneLocation{AreaCode: "55", State: "Ciudad de México", MainCity: "Ciudad de México"}, exists: true},
+		{digits: "3383527411", location: entities.PhoneLocation{AreaCode: "33", State: "Jalisco", MainCity: "Guadalajara"}, exists: true},
+		{digits: "9618352741", location: entities.PhoneLocation{AreaCode: "961", State: "Chiapas", MainCity: "Tuxtla Gutiérrez"}, exists: true},
+		{digits: "9948352741", location: entities.PhoneLocation{AreaCode: "994", State: "Chiapas"}, exists: true},
+		{digits: "1008352741"},
+		{digits: "9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			location, exists := lookupAreaCode(tt.digits)
+			if exists != tt.exists || location != tt.location {
+				t.Errorf("lookupAreaCode(%s) = %+v, %v; se esperaba %+v, %v", tt.digits, location, exists, tt.location, tt.exists)
+			}
+		})
+	}
+}
+
+func TestIsRealSubscriber(t *testing.T) {
+	tests := []struct {
+		digits   string
+		areaCode string
+		real     bool
+	}{
+		{digits: "9618352741", areaCode: "961", real: true},
+		{digits: "5583527411", areaCode: "55", real: true},
+		{digits: "9611111111", areaCode: "961"},
+		{digits: "9611234567", areaCode: "961"},
+		{digits: "9617654321", areaCode: "961"},
+		{digits: "5512345678", areaCode: "55"},
+		{digits: "961", areaCode: "961"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			if got := isRealSubscriber(tt.digits, tt.areaCode); got != tt.real {
+				t.Errorf("isRealSubscriber(%s, %s) = %v, se esperaba %v", tt.digits, tt.areaCode, got, tt.real)
+			}
+		})
+	}
+}
+
+func TestPhoneNumberingPlanRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy entities.PhonePolicy
+		phone  string
+		errors []string
+	}{
+		{name: "lada del plan", phone: "55 8352 7411"},
+		{name: "lada inexistente", phone: "100 835 2741", errors: []string{"UNKNOWN_AREA_CODE"}},
+		{name: "número repetido", phone: "961 111 1111", errors: []string{"INVALID_NUMBER"}},
+		{name: "con prefijo de celular", phone: "044 961 835 2741"},
+		{name: "lada permitida", policy: entities.PhonePolicy{AreaCodes: []string{"961", "962"}}, phone: "962 835 2741"},
+		{name: "lada no permitida", policy: entities.PhonePolicy{AreaCodes: []string{"961", "962"}}, phone: "993 835 2741", errors: []string{"INVALID_AREA_CODE"}},
+		{name: "estado permitido", policy: entities.PhonePolicy{States: []string{"Chiapas", "Tabasco"}}, phone: "993 835 2741"},
+		{name: "estado no permitido", policy: entities.PhonePolicy{States: []string{"Chiapas", "Tabasco"}}, phone: "999 835 2741", errors: []string{"INVALID_AREA_CODE"}},
+		{name: "extranjero con ladas restringidas", policy: entities.PhonePolicy{States: []string{"Chiapas"}}, phone: "+34 612 935 278", errors: []string{"INVALID_AREA_CODE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := DefaultValidationProfile()
+			profile.Phone = tt.policy
+			validator, err := newProfileValidator(profile)
+			if err != nil {
+				t.Fatalf("newProfileValidator() error = %v", err)
+			}
+
+			var types []string
+			for _, validationError := range validator.ValidateField(entities.FieldPhone, tt.phone) {
+				types = append(types, validationError.Type)
+			}
+			if !reflect.DeepEqual(types, tt.errors) {
+				t.Errorf("ValidateField(%s) = %v, se esperaba %v", tt.phone, types, tt.errors)
+			}
+		})
+	}
+}
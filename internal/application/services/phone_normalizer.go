@@ -0,0 +1,214 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// defaultPhoneCountry es el país de los teléfonos escritos sin código de país
+const defaultPhoneCountry = "MX"
+
+// extensionRegex reconoce una extensión al final del teléfono (ext 12, ext. 12, x12, #12)
+var extensionRegex = regexp.MustCompile(`(?i)\s*(?:ext(?:ensi[oó]n)?\.?|x|#)\s*(\d{1,6})\s*$`)
+
+// phoneCountry describe las reglas de numeración de un país
+type phoneCountry struct {
+	code        string // ISO 3166-1 alfa-2
+	callingCode string
+	minLength   int // longitud mínima del número nacional significativo
+	maxLength   int // longitud máxima del número nacional significativo
+	trunkPrefix string
+	// mobilePrefix se marca después del código de país en algunos celulares pero no
+	// forma parte del número (el 1 de México o el 9 de Argentina)
+	mobilePrefix string
+}
+
+// phoneCountries lista los países reconocidos con su código y longitud de número nacional
+var phoneCountries = []phoneCountry{
+	{code: "MX", callingCode: "52", minLength: 10, maxLength: 10, mobilePrefix: "1"},
+	{code: "US", callingCode: "1", minLength: 10, maxLength: 10, trunkPrefix: "1"},
+	{code: "GT", callingCode: "502", minLength: 8, maxLength: 8},
+	{code: "SV", callingCode: "503", minLength: 8, maxLength: 8},
+	{code: "HN", callingCode: "504", minLength: 8, maxLength: 8},
+	{code: "NI", callingCode: "505", minLength: 8, maxLength: 8},
+	{code: "CR", callingCode: "506", minLength: 8, maxLength: 8},
+	{code: "PA", callingCode: "507", minLength: 7, maxLength: 8},
+	{code: "CU", callingCode: "53", minLength: 8, maxLength: 8, trunkPrefix: "0"},
+	{code: "CO", callingCode: "57", minLength: 10, maxLength: 10},
+	{code: "VE", callingCode: "58", minLength: 10, maxLength: 10, trunkPrefix: "0"},
+	{code: "EC", callingCode: "593", minLength: 8, maxLength: 9, trunkPrefix: "0"},
+	{code: "PE", callingCode: "51", minLength: 8, maxLength: 9, trunkPrefix: "0"},
+	{code: "BO", callingCode: "591", minLength: 8, maxLength: 8, trunkPrefix: "0"},
+	{code: "CL", callingCode: "56", minLength: 9, maxLength: 9},
+	{code: "AR", callingCode: "54", minLength: 10, maxLength: 10, trunkPrefix: "0", mobilePrefix: "9"},
+	{code: "UY", callingCode: "598", minLength: 8, maxLength: 8, trunkPrefix: "0"},
+	{code: "PY", callingCode: "595", minLength: 9, maxLength: 9, trunkPrefix: "0"},
+	{code: "BR", callingCode: "55", minLength: 10, maxLength: 11, trunkPrefix: "0"},
+	{code: "ES", callingCode: "34", minLength: 9, maxLength: 9},
+	{code: "FR", callingCode: "33", minLength: 9, maxLength: 9, trunkPrefix: "0"},
+	{code: "DE", callingCode: "49", minLength: 6, maxLength: 11, trunkPrefix: "0"},
+	{code: "IT", callingCode: "39", minLength: 6, maxLength: 11},
+	{code: "GB", callingCode: "44", minLength: 10, maxLength: 10, trunkPrefix: "0"},
+	{code: "CN", callingCode: "86", minLength: 10, maxLength: 11, trunkPrefix: "0"},
+	{code: "JP", callingCode: "81", minLength: 9, maxLength: 10, trunkPrefix: "0"},
+	{code: "IN", callingCode: "91", minLength: 10, maxLength: 10, trunkPrefix: "0"},
+}
+
+// parsedPhone representa un teléfono separado en sus partes. Country queda vacío si el
+// número tiene un código de país que no se reconoce.
+type parsedPhone struct {
+	Country       string
+	CallingCode   string
+	National      string
+	Extension     string
+	International bool
+}
+
+// E164 obtiene el número en formato E.164 (+52 seguido del número nacional)
+func (p parsedPhone) E164() string {
+	return "+" + p.CallingCode + p.National
+}
+
+// ValidLength indica si el número nacional tiene una longitud válida para su país
+func (p parsedPhone) ValidLength() bool {
+	country, exists := countryByCode(p.Country)
+	return exists && len(p.National) >= country.minLength && len(p.National) <= country.maxLength
+}
+
+// parsePhone separa un teléfono en código de país, número nacional y extensión. Los
+// números con + o 00 se leen como internacionales; los demás se consideran de México y
+// se les quitan los prefijos de marcación nacionales (01, 044, 045) o el 52 sin +.
+func parsePhone(phone string) parsedPhone {
+	text := strings.TrimSpace(phone)
+	var parsed parsedPhone
+	if match := extensionRegex.FindStringSubmatchIndex(text); match != nil {
+		parsed.Extension = text[match[2]:match[3]]
+		text = strings.TrimSpace(text[:match[0]])
+	}
+
+	digits := nonDigitRegex.ReplaceAllString(text, "")
+	switch {
+	case strings.HasPrefix(text, "+"):
+		parsed.International = true
+	case strings.HasPrefix(digits, "00"):
+		parsed.International = true
+		digits = digits[2:]
+	}
+
+	if !parsed.International {
+		country, _ := countryByCode(defaultPhoneCountry)
+		parsed.Country = country.code
+		parsed.CallingCode = country.callingCode
+		parsed.National = digits
+		if national, ok := nationalNumber(digits); ok {
+			parsed.National = national
+		}
+		return parsed
+	}
+
+	country, exists := countryByCallingCode(digits)
+	if !exists {
+		parsed.National = digits
+		return parsed
+	}
+
+	national := digits[len(country.callingCode):]
+	for _, prefix := range []string{country.mobilePrefix, country.trunkPrefix} {
+		if prefix != "" && len(national) > country.maxLength && strings.HasPrefix(national, prefix) {
+			national = national[len(prefix):]
+		}
+	}
+
+	parsed.Country = country.code
+	parsed.CallingCode = country.callingCode
+	parsed.National = national
+	return parsed
+}
+
+// countryByCode obtiene las reglas de numeración de un país por su código ISO
+func countryByCode(code string) (phoneCountry, bool) {
+	for _, country := range phoneCountries {
+		if country.code == code {
+			return country, true
+		}
+	}
+	return phoneCountry{}, false
+}
+
+// countryByCallingCode obtiene el país cuyo código de marcación es prefijo de los dígitos,
+// prefiriendo el código más largo
+func countryByCallingCode(digits string) (phoneCountry, bool) {
+	var found phoneCountry
+	for _, country := range phoneCountries {
+		if strings.HasPrefix(digits, country.callingCode) && len(country.callingCode) > len(found.callingCode) {
+			found = country
+		}
+	}
+	return found, found.code != ""
+}
+
+// nationalNumber obtiene el número nacional de 10 dígitos de México quitando los
+// prefijos de marcación
+func nationalNumber(digits string) (string, bool) {
+	if len(digits) == 10 {
+		return digits, true
+	}
+	for _, prefix := range nationalPrefixes {
+		if len(digits) == len(prefix)+10 && strings.HasPrefix(digits, prefix) {
+			return digits[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+// enrichPhone completa el formato E.164, la extensión y la ubicación del teléfono del
+// contacto; los quita si el teléfono no puede normalizarse
+func enrichPhone(contact *entities.Contact) {
+	contact.PhoneE164 = ""
+	contact.PhoneExtension = ""
+	contact.PhoneLocation = nil
+
+	parsed := parsePhone(contact.Phone)
+	if !parsed.ValidLength() {
+		return
+	}
+
+	contact.PhoneE164 = parsed.E164()
+	contact.PhoneExtension = parsed.Extension
+	if parsed.Country == defaultPhoneCountry {
+		if location, exists := lookupAreaCode(parsed.National); exists {
+			contact.PhoneLocation = &location
+		}
+	}
+}
+
+// normalizePhone escribe un teléfono en su forma canónica: los de México como número
+// nacional de 10 dígitos y los de otros países en formato E.164, con la extensión al final.
+// Un número sin + ni 00 que no es de México se lee como internacional. Si el teléfono no
+// puede normalizarse se retorna sin cambios.
+func normalizePhone(phone string) string {
+	parsed := parsePhone(phone)
+	if !parsed.International && !parsed.ValidLength() {
+		foreign := parsePhone("+" + parsed.National)
+		if foreign.Country != defaultPhoneCountry && foreign.ValidLength() {
+			foreign.Extension = parsed.Extension
+			parsed = foreign
+		}
+	}
+	if !parsed.ValidLength() {
+		return phone
+	}
+
+	number := parsed.E164()
+	if parsed.Country == defaultPhoneCountry {
+		number = parsed.National
+	}
+	return withExtension(number, parsed.Extension)
+}
+
+// normalizeDisplayPhone conserva el teléfono como se escribió, sin espacios repetidos
+func normalizeDisplayPhone(phone string) string {
+	return strings.Join(strings.Fields(phone), " ")
+}
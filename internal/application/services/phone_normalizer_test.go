@@ -0,0 +1,141 @@
+package services
+
+import (
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		phone  string
+		parsed parsedPhone
+		valid  bool
+	}{
+		{phone: "961 835 2741", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741"}, valid: true},
+		{phone: "01 (961) 835-2741", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741"}, valid: true},
+		{phone: "529618352741", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741"}, valid: true},
+		{phone: "+52 1 961 835 2741", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741", International: true}, valid: true},
+		{phone: "0052 961 835 2741", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741", International: true}, valid: true},
+		{phone: "961 835 2741 ext. 12", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741", Extension: "12"}, valid: true},
+		{phone: "961 835 2741 x5", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "9618352741", Extension: "5"}, valid: true},
+		{phone: "+1 (212) 555-0142", parsed: parsedPhone{Country: "US", CallingCode: "1", National: "2125550142", International: true}, valid: true},
+		{phone: "+502 2345 6789", parsed: parsedPhone{Country: "GT", CallingCode: "502", National: "23456789", International: true}, valid: true},
+		{phone: "+54 9 11 2345 6789", parsed: parsedPhone{Country: "AR", CallingCode: "54", National: "1123456789", International: true}, valid: true},
+		{phone: "+44 020 7946 0958", parsed: parsedPhone{Country: "GB", CallingCode: "44", National: "2079460958", International: true}, valid: true},
+		{phone: "+34 612 93", parsed: parsedPhone{Country: "ES", CallingCode: "34", National: "61293", International: true}},
+		{phone: "+999 123 456", parsed: parsedPhone{National: "999123456", International: true}},
+		{phone: "961 835 274", parsed: parsedPhone{Country: "MX", CallingCode: "52", National: "961835274"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			parsed := parsePhone(tt.phone)
+			if parsed != tt.parsed {
+				t.Errorf("parsePhone(%q) = %+v, se esperaba %+v", tt.phone, parsed, tt.parsed)
+			}
+			if parsed.ValidLength() != tt.valid {
+				t.Errorf("ValidLength() = %v, se esperaba %v", parsed.ValidLength(), tt.valid)
+			}
+		})
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone      string
+		normalized string
+	}{
+		{phone: "(961) 835-2741", normalized: "9618352741"},
+		{phone: "+52 1 961 835 2741", normalized: "9618352741"},
+		{phone: "044 961 835 2741 ext 12", normalized: "9618352741 ext. 12"},
+		{phone: "+1 212 555 0142", normalized: "+12125550142"},
+		{phone: "0034 612 935 278", normalized: "+34612935278"},
+		{phone: "34 612 935 278", normalized: "+34612935278"},
+		{phone: "961 835 274", normalized: "961 835 274"},
+		{phone: "+999 123 456", normalized: "+999 123 456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			if got := normalizePhone(tt.phone); got != tt.normalized {
+				t.Errorf("normalizePhone(%q) = %q, se esperaba %q", tt.phone, got, tt.normalized)
+			}
+		})
+	}
+}
+
+func TestEnrichPhone(t *testing.T) {
+	tests := []struct {
+		phone     string
+		e164      string
+		extension string
+		state     string
+	}{
+		{phone: "961 835 2741 ext 3", e164: "+529618352741", extension: "3", state: "Chiapas"},
+		{phone: "+52 55 8352 7411", e164: "+525583527411", state: "Ciudad de México"},
+		{phone: "100 835 2741", e164: "+521008352741"},
+		{phone: "+1 212 555 0142", e164: "+12125550142"},
+		{phone: "12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			// Los datos del teléfono anterior deben reemplazarse
+			contact := &entities.Contact{
+				Phone:          tt.phone,
+				PhoneE164:      "+520000000000",
+				PhoneExtension: "9",
+				PhoneLocation:  &entities.PhoneLocation{AreaCode: "999"},
+			}
+			enrichPhone(contact)
+
+			state := ""
+			if contact.PhoneLocation != nil {
+				state = contact.PhoneLocation.State
+			}
+			if contact.PhoneE164 != tt.e164 || contact.PhoneExtension != tt.extension || state != tt.state {
+				t.Errorf("enrichPhone(%q) = %s, %q, %q; se esperaba %s, %q, %q",
+					tt.phone, contact.PhoneE164, contact.PhoneExtension, state, tt.e164, tt.extension, tt.state)
+			}
+		})
+	}
+}
+
+func TestInternationalPhoneRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		countries []string
+		phone     string
+		errorType string
+	}{
+		{name: "número de otro país", phone: "+1 212 555 0142"},
+		{name: "código de país desconocido", phone: "+999 123 456", errorType: "UNKNOWN_COUNTRY_CODE"},
+		{name: "longitud inválida para el país", phone: "+34 612 93", errorType: "INVALID_LENGTH"},
+		{name: "longitud inválida en México", phone: "+52 961 835 274", errorType: "INVALID_LENGTH"},
+		{name: "país permitido", countries: []string{"mx", "us"}, phone: "+1 212 555 0142"},
+		{name: "país no permitido", countries: []string{"MX"}, phone: "+1 212 555 0142", errorType: "INVALID_COUNTRY"},
+		{name: "letras en el número", phone: "961 TAXI 41", errorType: "INVALID_CHARACTER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := DefaultValidationProfile()
+			profile.Phone = entities.PhonePolicy{Countries: tt.countries}
+			validator, err := newProfileValidator(profile)
+			if err != nil {
+				t.Fatalf("newProfileValidator() error = %v", err)
+			}
+
+			errorType := ""
+			for _, validationError := range validator.ValidateField(entities.FieldPhone, tt.phone) {
+				if validationError.Severity == entities.SeverityError {
+					errorType = validationError.Type
+				}
+			}
+			if errorType != tt.errorType {
+				t.Errorf("ValidateField(%s) = %q, se esperaba %q", tt.phone, errorType, tt.errorType)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/validation"
+)
+
+// RuleRegistry guarda las reglas de validación disponibles por nombre
+type RuleRegistry struct {
+	rules map[string]validation.Rule
+	mutex sync.RWMutex
+}
+
+// NewRuleRegistry crea un registro con las reglas indicadas
+func NewRuleRegistry(rules ...validation.Rule) *RuleRegistry {
+	registry := &RuleRegistry{rules: make(map[string]validation.Rule)}
+	for _, rule := range rules {
+		registry.rules[rule.Name()] = rule
+	}
+	return registry
+}
+
+// Register agrega una regla al registro; el nombre no debe estar en uso
+func (r *RuleRegistry) Register(rule validation.Rule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.rules[rule.Name()]; exists {
+		return fmt.Errorf("ya existe una regla con el nombre %s", rule.Name())
+	}
+	r.rules[rule.Name()] = rule
+	return nil
+}
+
+// Get obtiene una regla por nombre
+func (r *RuleRegistry) Get(name string) (validation.Rule, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rule, exists := r.rules[name]
+	return rule, exists
+}
+
+// Resolve obtiene las reglas con los nombres indicados, en el mismo orden
+func (r *RuleRegistry) Resolve(names []string) ([]validation.Rule, error) {
+	rules := make([]validation.Rule, 0, len(names))
+	for _, name := range names {
+		rule, exists := r.Get(name)
+		if !exists {
+			return nil, fmt.Errorf("regla de validación desconocida: %s", name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// All obtiene todas las reglas registradas ordenadas por nombre
+func (r *RuleRegistry) All() []validation.Rule {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rules := make([]validation.Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name() < rules[j].Name() })
+	return rules
+}
+
+// funcRule implementa una regla de validación a partir de una función
+type funcRule struct {
+	name     string
+	field    string
+	code     string
+	severity string
+	message  string
+	check    func(string) bool
+}
+
+// NewRule crea una regla de validación que usa la función indicada para revisar el valor
+func NewRule(name, field, code, severity, message string, check func(string) bool) validation.Rule {
+	return &funcRule{
+		name:     name,
+		field:    field,
+		code:     code,
+		severity: severity,
+		message:  message,
+		check:    check,
+	}
+}
+
+func (r *funcRule) Name() string            { return r.name }
+func (r *funcRule) Field() string           { return r.field }
+func (r *funcRule) Code() string            { return r.code }
+func (r *funcRule) Severity() string        { return r.severity }
+func (r *funcRule) Message() string         { return r.message }
+func (r *funcRule) Check(value string) bool { return r.check(value) }
+
+// phoneRule implementa una regla de teléfono que revisa el número ya separado en sus
+// partes, para que la validación de un contacto lo analice una sola vez
+type phoneRule struct {
+	funcRule
+	checkPhone func(parsedPhone) bool
+}
+
+// newPhoneRule crea una regla del campo teléfono que revisa el número analizado
+func newPhoneRule(name, code, severity, message string, check func(parsedPhone) bool) validation.Rule {
+	return &phoneRule{
+		funcRule: funcRule{
+			name:     name,
+			field:    entities.FieldPhone,
+			code:     code,
+			severity: severity,
+			message:  message,
+		},
+		checkPhone: check,
+	}
+}
+
+func (r *phoneRule) Check(value string) bool { return r.checkPhone(parsePhone(value)) }
+
+// checkRule revisa el valor con la regla. Las reglas de teléfono reciben el número ya
+// analizado del valor en lugar de volver a analizarlo.
+func checkRule(rule validation.Rule, value string, phone parsedPhone) bool {
+	switch r := rule.(type) {
+	case *phoneRule:
+		return r.checkPhone(phone)
+	case *severityRule:
+		return checkRule(r.Rule, value, phone)
+	}
+	return rule.Check(value)
+}
+
+// severityRule cambia la severidad de una regla sin modificar cómo se revisa el valor
+type severityRule struct {
+	validation.Rule
+	severity string
+}
+
+// WithSeverity crea una copia de la regla que reporta sus errores con otra severidad
+func WithSeverity(rule validation.Rule, severity string) (validation.Rule, error) {
+	switch severity {
+	case entities.SeverityError, entities.SeverityWarning, entities.SeverityInfo:
+		return &severityRule{Rule: rule, severity: severity}, nil
+	default:
+		return nil, fmt.Errorf("severidad desconocida para la regla %s: %s", rule.Name(), severity)
+	}
+}
+
+func (r *severityRule) Severity() string { return r.severity }
+
+// DescribeRules lista las reglas registradas e indica cuáles ejecuta el validador y en qué orden
+func (v *ValidatorService) DescribeRules() []entities.RuleInfo {
+	order := make(map[string]int)
+	for i, rule := range v.Rules() {
+		order[rule.Name()] = i + 1
+	}
+
+	var infos []entities.RuleInfo
+	for _, rule := range v.registry.All() {
+		infos = append(infos, entities.RuleInfo{
+			Name:     rule.Name(),
+			Field:    rule.Field(),
+			Code:     rule.Code(),
+			Severity: rule.Severity(),
+			Message:  rule.Message(),
+			Active:   order[rule.Name()] > 0,
+			Order:    order[rule.Name()],
+		})
+	}
+	return infos
+}
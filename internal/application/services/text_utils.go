@@ -0,0 +1,122 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// accentReplacer elimina los acentos y diacríticos comunes del español
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"à", "a", "è", "e", "ì", "i", "ò", "o", "ù", "u",
+	"â", "a", "ê", "e", "î", "i", "ô", "o", "û", "u",
+	"ä", "a", "ë", "e", "ï", "i", "ö", "o", "ç", "c",
+)
+
+// normalizeText convierte un texto a minúsculas sin acentos, reemplaza los
+// caracteres no alfanuméricos por espacios y colapsa los espacios repetidos
+func normalizeText(text string) string {
+	text = accentReplacer.Replace(strings.ToLower(text))
+
+	var builder strings.Builder
+	for _, char := range text {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			builder.WriteRune(char)
+		} else {
+			builder.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// levenshtein calcula la distancia de edición entre dos cadenas
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// similarity retorna la similitud entre 0 y 1 de dos cadenas según su distancia de edición
+func similarity(a, b string) float64 {
+	longest := len([]rune(a))
+	if l := len([]rune(b)); l > longest {
+		longest = l
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// containsString indica si el texto está en la lista
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// columnName convierte un índice de columna (base 0) a su letra de hoja de cálculo (A, B, ..., AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// columnIndex convierte una letra de columna (A, B, ..., AA) a su índice base 0
+func columnIndex(name string) (int, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" || len(name) > 3 {
+		return 0, false
+	}
+
+	index := 0
+	for _, char := range name {
+		if char < 'A' || char > 'Z' {
+			return 0, false
+		}
+		index = index*26 + int(char-'A'+1)
+	}
+	return index - 1, true
+}
+
+// newID genera un identificador aleatorio en hexadecimal
+func newID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/infrastructure/repositories"
+)
+
+var (
+	benchContacts = flag.Int("bench.contacts", 1_000_000, "número de contactos sintéticos de BenchmarkValidateContacts")
+	benchInvalid  = flag.Float64("bench.invalid", 0.2, "proporción de contactos con algún error")
+)
+
+// BenchmarkValidateContacts mide la validación completa (reglas, claves repetidas y claves
+// sin ceros) de un dataset sintético con uno y con todos los workers, y reporta contactos
+// por segundo. Por defecto valida un millón de contactos; -bench.contacts cambia el tamaño:
+//
+//	go test ./internal/application/services -run '^$' -bench ValidateContacts -bench.contacts 100000
+func BenchmarkValidateContacts(b *testing.B) {
+	contacts := syntheticContacts(*benchContacts, *benchInvalid, rand.New(rand.NewSource(1)))
+	contactService := NewContactService(
+		repositories.NewInMemoryContactRepository(repositories.ContactRepositoryOptions{}),
+		NewValidatorService(),
+	)
+
+	workerCounts := []int{1}
+	if cpus := runtime.GOMAXPROCS(0); cpus > 1 {
+		workerCounts = append(workerCounts, cpus)
+	}
+
+	var reference []*entities.ContactWithValidation
+	for _, workers := range workerCounts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			contactService.SetValidationWorkers(workers)
+			b.ReportAllocs()
+
+			var results []*entities.ContactWithValidation
+			for i := 0; i < b.N; i++ {
+				var err error
+				if results, err = contactService.ValidateContacts(contacts, ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(contacts)*b.N)/b.Elapsed().Seconds(), "contactos/s")
+
+			// Todos los números de workers deben producir los mismos resultados en el mismo orden
+			if reference == nil {
+				reference = results
+			} else if !reflect.DeepEqual(reference, results) {
+				b.Fatalf("los resultados con %d workers difieren de los de un worker", workers)
+			}
+		})
+	}
+}
+
+// syntheticContacts genera contactos con claves de 6 dígitos, dominios personales y
+// teléfonos de Chiapas; la proporción indicada recibe un error en un campo al azar
+func syntheticContacts(count int, invalidShare float64, random *rand.Rand) []*entities.Contact {
+	firstNames := []string{"Juan", "María", "José", "Ana", "Luis", "Carmen", "Pedro", "Lucía"}
+	lastNames := []string{"Pérez", "López", "García", "Hernández", "Gómez", "Díaz", "Ruiz", "O'Neil"}
+	domains := []string{"gmail.com", "hotmail.com", "yahoo.com", "outlook.com"}
+	areaCodes := []string{"961", "962", "963", "964", "965", "966", "967", "968"}
+
+	contacts := make([]*entities.Contact, count)
+	for i := range contacts {
+		contact := &entities.Contact{
+			ID:        i + 1,
+			ClientKey: fmt.Sprintf("%06d", i),
+			Name:      firstNames[random.Intn(len(firstNames))] + " " + lastNames[random.Intn(len(lastNames))],
+			Email:     fmt.Sprintf("cliente%d@%s", i, domains[random.Intn(len(domains))]),
+			Phone:     fmt.Sprintf("%s%d", areaCodes[random.Intn(len(areaCodes))], 2000000+random.Intn(7000000)),
+		}
+
+		if random.Float64() < invalidShare {
+			switch random.Intn(4) {
+			case 0:
+				contact.ClientKey = strconv.Itoa(i)
+			case 1:
+				contact.Name += "#"
+			case 2:
+				contact.Email = strings.Replace(contact.Email, ".com", ",com", 1)
+			case 3:
+				contact.Phone = "55" + contact.Phone[3:]
+			}
+		}
+		contacts[i] = contact
+	}
+	return contacts
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// validationCache guarda los resultados de validación de cada dataset por perfil. Los
+// errores de las reglas se guardan por contacto junto con su fecha de actualización, de
+// modo que solo se vuelven a validar los contactos que Save, Update o SaveBatch tocaron
+// desde la última validación. Un cambio en las reglas del perfil descarta sus resultados.
+type validationCache struct {
+	datasets map[string]map[string]*cachedValidation
+	mutex    sync.Mutex
+}
+
+// cachedValidation representa la última validación de un dataset con un perfil
+type cachedValidation struct {
+	validator *ValidatorService
+	version   uint64
+	contacts  map[int]cachedContact
+	results   []*entities.ContactWithValidation
+}
+
+// cachedContact representa los errores de las reglas de un contacto en la versión validada
+type cachedContact struct {
+	updatedAt time.Time
+	errors    []entities.ValidationError
+}
+
+// newValidationCache crea un caché de validación vacío
+func newValidationCache() *validationCache {
+	return &validationCache{datasets: make(map[string]map[string]*cachedValidation)}
+}
+
+// get obtiene la última validación del dataset con el validador indicado, si las reglas
+// no han cambiado desde entonces
+func (c *validationCache) get(datasetID string, validator *ValidatorService) *cachedValidation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached := c.datasets[datasetID][validator.Profile().Name]
+	if cached == nil || cached.validator != validator || cached.version != validator.RulesVersion() {
+		return nil
+	}
+	return cached
+}
+
+// put guarda la validación de un dataset
+func (c *validationCache) put(datasetID string, cached *cachedValidation) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.datasets[datasetID] == nil {
+		c.datasets[datasetID] = make(map[string]*cachedValidation)
+	}
+	c.datasets[datasetID][cached.validator.Profile().Name] = cached
+}
+
+// forget descarta las validaciones de un dataset
+func (c *validationCache) forget(datasetID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.datasets, datasetID)
+}
+
+// unchanged indica si los contactos son los mismos que se validaron, en el mismo orden y
+// sin actualizaciones posteriores
+func (v *cachedValidation) unchanged(contacts []*entities.Contact) bool {
+	if len(contacts) != len(v.results) {
+		return false
+	}
+	for i, contact := range contacts {
+		cached, exists := v.contacts[contact.ID]
+		if !exists || v.results[i].Contact.ID != contact.ID || !cached.updatedAt.Equal(contact.UpdatedAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleErrors obtiene los errores de las reglas de un contacto si no cambió desde la última validación
+func (v *cachedValidation) ruleErrors(contact *entities.Contact) ([]entities.ValidationError, bool) {
+	if v == nil {
+		return nil, false
+	}
+	cached, exists := v.contacts[contact.ID]
+	if !exists || !cached.updatedAt.Equal(contact.UpdatedAt) {
+		return nil, false
+	}
+	return cached.errors, true
+}
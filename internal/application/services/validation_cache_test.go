@@ -0,0 +1,123 @@
+package services
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/infrastructure/repositories"
+)
+
+func TestValidationCacheRevalidatesChangedContacts(t *testing.T) {
+	// La regla de conteo registra cuántos contactos pasan por las reglas en cada validación
+	var validated atomic.Int64
+	validator := NewValidatorService()
+	counter := NewRule("name.counted", entities.FieldName, "COUNTED", entities.SeverityWarning, "",
+		func(string) bool {
+			validated.Add(1)
+			return true
+		})
+	if err := validator.Registry().Register(counter); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := validator.SetRules(append(append([]string(nil), DefaultRuleNames...), counter.Name())); err != nil {
+		t.Fatalf("SetRules() error = %v", err)
+	}
+
+	contacts := NewContactService(repositories.NewInMemoryContactRepository(repositories.ContactRepositoryOptions{}), validator)
+	saved := []*entities.Contact{
+		{ClientKey: "1", Name: "Ana", Email: "ana@gmail.com", Phone: "9618352741"},
+		{ClientKey: "2", Name: "Beto", Email: "beto@gmail.com", Phone: "9618352742"},
+		{ClientKey: "3", Name: "Carla", Email: "carla@gmail.com", Phone: "9618352743"},
+	}
+	if err := contacts.SaveContactsBatch("d", saved); err != nil {
+		t.Fatalf("SaveContactsBatch() error = %v", err)
+	}
+
+	// Los pasos se ejecutan en orden sobre el mismo dataset
+	tests := []struct {
+		name   string
+		change func(t *testing.T)
+		// validated es el número de contactos que vuelven a pasar por las reglas
+		validated int64
+		total     int
+		invalid   int
+	}{
+		{
+			name:      "primera validación",
+			change:    func(t *testing.T) {},
+			validated: 3,
+			total:     3,
+		},
+		{
+			name:   "sin cambios",
+			change: func(t *testing.T) {},
+			total:  3,
+		},
+		{
+			name: "contacto actualizado",
+			change: func(t *testing.T) {
+				changed := *saved[1]
+				changed.Email = "beto"
+				if err := contacts.UpdateContact("d", &changed); err != nil {
+					t.Fatalf("UpdateContact() error = %v", err)
+				}
+			},
+			validated: 1,
+			total:     3,
+			invalid:   1,
+		},
+		{
+			name: "contacto agregado con una clave repetida",
+			change: func(t *testing.T) {
+				added := &entities.Contact{ClientKey: "1", Name: "Dora", Email: "dora@gmail.com", Phone: "9618352744"}
+				if err := contacts.SaveContactsBatch("d", []*entities.Contact{added}); err != nil {
+					t.Fatalf("SaveContactsBatch() error = %v", err)
+				}
+			},
+			validated: 1,
+			total:     4,
+			invalid:   3,
+		},
+		{
+			name: "contacto eliminado",
+			change: func(t *testing.T) {
+				if err := contacts.DeleteContact("d", saved[0].ID); err != nil {
+					t.Fatalf("DeleteContact() error = %v", err)
+				}
+			},
+			total:   3,
+			invalid: 1,
+		},
+		{
+			name: "reglas cambiadas",
+			change: func(t *testing.T) {
+				if err := validator.SetRules(append(append([]string(nil), DefaultRuleNames...), counter.Name())); err != nil {
+					t.Fatalf("SetRules() error = %v", err)
+				}
+			},
+			validated: 3,
+			total:     3,
+			invalid:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change(t)
+			validated.Store(0)
+
+			results, err := contacts.ValidateAllContacts("d", "")
+			if err != nil {
+				t.Fatalf("ValidateAllContacts() error = %v", err)
+			}
+			if got := validated.Load(); got != tt.validated {
+				t.Errorf("se validaron %d contactos, se esperaban %d", got, tt.validated)
+			}
+			stats := SummarizeResults(results)
+			if stats.Total != tt.total || stats.Invalid != tt.invalid {
+				t.Errorf("resultados = %d contactos, %d inválidos; se esperaban %d y %d", stats.Total, stats.Invalid, tt.total, tt.invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// CheckValidationFilter revisa que el filtro use una validez y un campo conocidos
+func CheckValidationFilter(filter entities.ValidationFilter) error {
+	switch filter.Validity {
+	case "", entities.ValidityValid, entities.ValidityInvalid, entities.ValidityWarnings:
+	default:
+		return fmt.Errorf("filtro de validez desconocido: %s (use %s, %s o %s)", filter.Validity,
+			entities.ValidityValid, entities.ValidityInvalid, entities.ValidityWarnings)
+	}
+
+	if filter.Field != "" && !containsString(entities.ContactFields, filter.Field) {
+		return fmt.Errorf("campo desconocido: %s", filter.Field)
+	}
+	return nil
+}
+
+// FilterResults obtiene los resultados de validación que cumplen el filtro, en su orden original
+func FilterResults(results []*entities.ContactWithValidation, filter entities.ValidationFilter) []*entities.ContactWithValidation {
+	text := accentReplacer.Replace(strings.ToLower(strings.TrimSpace(filter.Text)))
+
+	filtered := make([]*entities.ContactWithValidation, 0, len(results))
+	for _, result := range results {
+		if matchesValidity(result, filter.Validity) &&
+			matchesError(result, filter.Field, filter.Type) &&
+			matchesText(&result.Contact, text) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// matchesValidity indica si el resultado tiene la validez indicada. Los contactos válidos
+// con advertencias cumplen tanto el filtro de válidos como el de advertencias.
+func matchesValidity(result *entities.ContactWithValidation, validity string) bool {
+	switch validity {
+	case entities.ValidityValid:
+		return result.IsValid
+	case entities.ValidityInvalid:
+		return !result.IsValid
+	case entities.ValidityWarnings:
+		return result.Status == entities.ValidationStatusValidWithWarnings
+	}
+	return true
+}
+
+// matchesError indica si el resultado tiene un error del campo y tipo indicados
+func matchesError(result *entities.ContactWithValidation, field, errorType string) bool {
+	if field == "" && errorType == "" {
+		return true
+	}
+	for _, validationError := range result.Errors {
+		if (field == "" || validationError.Field == field) &&
+			(errorType == "" || strings.EqualFold(validationError.Type, errorType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesText indica si algún campo del contacto contiene el texto, sin distinguir
+// mayúsculas ni acentos
+func matchesText(contact *entities.Contact, text string) bool {
+	if text == "" {
+		return true
+	}
+	for _, value := range []string{contact.ClientKey, contact.Name, contact.Email, contact.Phone} {
+		if strings.Contains(accentReplacer.Replace(strings.ToLower(value)), text) {
+			return true
+		}
+	}
+	return false
+}
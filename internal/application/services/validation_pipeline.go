@@ -0,0 +1,61 @@
+package services
+
+import (
+	"runtime"
+	"sync"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// validationBatchSize es el número de contactos que toma un worker en cada turno
+const validationBatchSize = 256
+
+// validationWorkers obtiene el número de workers a usar; cero o negativo usa un worker por CPU
+func validationWorkers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// validateInParallel ejecuta validate sobre cada contacto con un pool de workers que toman
+// lotes consecutivos de la lista. Cada resultado se guarda en la posición de su contacto,
+// así que el orden no depende de cuál worker termina primero. validate debe poder llamarse
+// desde varias goroutines a la vez.
+func validateInParallel(contacts []*entities.Contact, workers int, validate func(*entities.Contact) []entities.ValidationError) [][]entities.ValidationError {
+	errors := make([][]entities.ValidationError, len(contacts))
+
+	workers = validationWorkers(workers)
+	if batches := (len(contacts) + validationBatchSize - 1) / validationBatchSize; batches < workers {
+		workers = batches
+	}
+	if workers <= 1 {
+		for i, contact := range contacts {
+			errors[i] = validate(contact)
+		}
+		return errors
+	}
+
+	starts := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := min(start+validationBatchSize, len(contacts))
+				for i := start; i < end; i++ {
+					errors[i] = validate(contacts[i])
+				}
+			}
+		}()
+	}
+
+	for start := 0; start < len(contacts); start += validationBatchSize {
+		starts <- start
+	}
+	close(starts)
+	wg.Wait()
+
+	return errors
+}
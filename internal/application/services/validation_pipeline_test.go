@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+func TestValidateInParallelKeepsInputOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		contacts int
+		workers  int
+	}{
+		{name: "sin contactos", contacts: 0, workers: 4},
+		{name: "un contacto", contacts: 1, workers: 4},
+		{name: "un worker", contacts: 3*validationBatchSize + 7, workers: 1},
+		{name: "dos workers", contacts: 3*validationBatchSize + 7, workers: 2},
+		{name: "más workers que lotes", contacts: 2*validationBatchSize + 1, workers: 16},
+		{name: "un worker por CPU", contacts: 10*validationBatchSize + 3, workers: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts := make([]*entities.Contact, tt.contacts)
+			for i := range contacts {
+				contacts[i] = &entities.Contact{ID: i + 1, ClientKey: fmt.Sprintf("%06d", i)}
+			}
+
+			// Los contactos pares ceden el procesador para que los lotes terminen desordenados
+			results := validateInParallel(contacts, tt.workers, func(contact *entities.Contact) []entities.ValidationError {
+				if contact.ID%2 == 0 {
+					runtime.Gosched()
+				}
+				return []entities.ValidationError{{Field: entities.FieldClientKey, Value: strconv.Itoa(contact.ID)}}
+			})
+
+			if len(results) != len(contacts) {
+				t.Fatalf("se obtuvieron %d resultados, se esperaban %d", len(results), len(contacts))
+			}
+			for i, errs := range results {
+				if len(errs) != 1 || errs[0].Value != strconv.Itoa(contacts[i].ID) {
+					t.Fatalf("el resultado %d es %+v, se esperaba el del contacto %d", i, errs, contacts[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// SummarizeValidation resume los resultados de validación por campo con los top
+// valores con errores más frecuentes de cada uno. Los campos se listan en el orden de ContactFields
+// seguidos de cualquier otro campo con errores.
+func SummarizeValidation(results []*entities.ContactWithValidation, top int) []entities.FieldSummary {
+	summaries := make(map[string]*entities.FieldSummary)
+	values := make(map[string]map[string]int)
+	fields := append([]string{}, entities.ContactFields...)
+
+	for _, field := range entities.ContactFields {
+		summaries[field] = newFieldSummary(field)
+		values[field] = make(map[string]int)
+	}
+
+	for _, result := range results {
+		for _, validationError := range result.Errors {
+			summary, exists := summaries[validationError.Field]
+			if !exists {
+				summary = newFieldSummary(validationError.Field)
+				summaries[validationError.Field] = summary
+				values[validationError.Field] = make(map[string]int)
+				fields = append(fields, validationError.Field)
+			}
+
+			summary.Errors++
+			summary.ByType[validationError.Type]++
+			summary.BySeverity[validationError.Severity]++
+			if value := offendingValue(validationError.Field, validationError.Value); value != "" {
+				values[validationError.Field][value]++
+			}
+		}
+	}
+
+	fieldSummaries := make([]entities.FieldSummary, 0, len(fields))
+	for _, field := range fields {
+		summary := summaries[field]
+		summary.TopValues = topValues(values[field], top)
+		fieldSummaries = append(fieldSummaries, *summary)
+	}
+	return fieldSummaries
+}
+
+// newFieldSummary crea el resumen vacío de un campo
+func newFieldSummary(field string) *entities.FieldSummary {
+	kind := entities.OffendingValueRaw
+	switch field {
+	case entities.FieldEmail:
+		kind = entities.OffendingValueDomain
+	case entities.FieldPhone:
+		kind = entities.OffendingValueAreaCode
+	}
+
+	return &entities.FieldSummary{
+		Field:      field,
+		ByType:     make(map[string]int),
+		BySeverity: make(map[string]int),
+		ValueKind:  kind,
+		TopValues:  []entities.ValueCount{},
+	}
+}
+
+// offendingValue obtiene la parte del valor que se agrupa en el resumen: el dominio
+// del email, la lada del teléfono (o el código de país si es extranjero) y el valor
+// completo de los demás campos o de los teléfonos con un código de país desconocido
+func offendingValue(field, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+
+	switch field {
+	case entities.FieldEmail:
+		if strings.Contains(value, "@") && emailDomain(value) != "" {
+			return emailDomain(value)
+		}
+		return strings.ToLower(value)
+
+	case entities.FieldPhone:
+		parsed := parsePhone(value)
+		if parsed.Country == "" {
+			return value
+		}
+		if parsed.Country != defaultPhoneCountry {
+			return "+" + parsed.CallingCode
+		}
+		if location, exists := lookupAreaCode(parsed.National); exists {
+			return location.AreaCode
+		}
+		if len(parsed.National) >= 3 {
+			return parsed.National[:3]
+		}
+		return parsed.National
+	}
+
+	return value
+}
+
+// topValues ordena los valores de mayor a menor frecuencia y conserva los primeros
+func topValues(counts map[string]int, top int) []entities.ValueCount {
+	values := make([]entities.ValueCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, entities.ValueCount{Value: value, Count: count})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	if top > 0 && len(values) > top {
+		values = values[:top]
+	}
+	return values
+}
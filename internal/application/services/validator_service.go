@@ -1,231 +1,468 @@
 package services
 
 import (
-	"regexp"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
-	"unicode"
+	"sync"
 
 	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/validation"
 )
 
 type ValidatorService struct {
-	validEmails   []string
-	chiapasLadas  []string
+	profile  entities.ValidationProfile
+	optional map[string]bool
+	registry *RuleRegistry
+	rules    []validation.Rule
+	version  uint64
+	mutex    sync.RWMutex
+
+	// Perfiles disponibles; solo el validador creado con NewValidatorService los conserva
+	profiles       map[string]*ValidatorService
+	profileNames   []string
+	defaultProfile string
 }
 
-// NewValidatorService crea una nueva instancia del servicio de validación
-func NewValidatorService() *ValidatorService {
-	return &ValidatorService{
-		validEmails: []string{
-			"gmail.com", "yahoo.com", "hotmail.com", "outlook.com",
-			"live.com", "icloud.com", "protonmail.com",
+// DefaultRuleNames lista las reglas que se ejecutan por defecto, en orden
+var DefaultRuleNames = []string{
+	"client_key.required", "client_key.format", "client_key.length",
+	"name.required", "name.characters",
+	"email.required", "email.format", "email.domain", "email.blocked_domain",
+	"phone.required", "phone.no_letters", "phone.country_code", "phone.country",
+	"phone.length", "phone.country_length",
+	"phone.numbering_plan", "phone.real_number", "phone.area_code", "phone.region",
+}
+
+// DefaultValidationProfile obtiene el perfil usado cuando no se configura otro:
+// dominios de email personales y teléfonos de 10 dígitos con lada de Chiapas
+func DefaultValidationProfile() entities.ValidationProfile {
+	return entities.ValidationProfile{
+		Name:        "chiapas",
+		Description: "Dominios de email personales y teléfonos con lada de Chiapas",
+		Names: entities.NamePolicy{
+			CharacterClasses: []string{
+				entities.CharacterClassLetters, entities.CharacterClassSpaces,
+				entities.CharacterClassApostrophes, entities.CharacterClassPeriods,
+			},
+		},
+		Email: entities.EmailPolicy{
+			AllowedDomains: []string{
+				"gmail.com", "yahoo.com", "hotmail.com", "outlook.com",
+				"live.com", "icloud.com", "protonmail.com",
+			},
 		},
-		chiapasLadas: []string{
-			"961", "962", "963", "964", "965", "966", "967", "968", "994",
+		Phone: entities.PhonePolicy{
+			Region: "Chiapas",
+			AreaCodes: []string{
+				"961", "962", "963", "964", "965", "966", "967", "968", "994",
+			},
+			Length: 10,
 		},
 	}
 }
 
-// ValidateContact valida todos los campos de un contacto
-func (v *ValidatorService) ValidateContact(contact *entities.Contact) []entities.ValidationError {
-	var errors []entities.ValidationError
+// NewValidatorService crea una nueva instancia del servicio de validación con el perfil por defecto
+func NewValidatorService() *ValidatorService {
+	v, err := newProfileValidator(DefaultValidationProfile())
+	if err != nil {
+		panic(err)
+	}
+
+	v.profiles = map[string]*ValidatorService{v.profile.Name: v}
+	v.profileNames = []string{v.profile.Name}
+	v.defaultProfile = v.profile.Name
+	return v
+}
 
-	// Validar clave cliente
-	if clientKeyErrors := v.validateClientKey(contact.ClientKey); len(clientKeyErrors) > 0 {
-		errors = append(errors, clientKeyErrors...)
+// newProfileValidator crea un validador que ejecuta las reglas configuradas por el perfil
+func newProfileValidator(profile entities.ValidationProfile) (*ValidatorService, error) {
+	if profile.Name == "" {
+		return nil, errors.New("el perfil de validación debe tener nombre")
+	}
+	if profile.Phone.Length == 0 {
+		profile.Phone.Length = 10
 	}
 
-	// Validar nombre
-	if nameErrors := v.validateName(contact.Name); len(nameErrors) > 0 {
-		errors = append(errors, nameErrors...)
+	builtins, err := profileRules(profile)
+	if err != nil {
+		return nil, fmt.Errorf("perfil %s: %w", profile.Name, err)
+	}
+	if builtins, err = applySeverities(builtins, profile.Severities); err != nil {
+		return nil, fmt.Errorf("perfil %s: %w", profile.Name, err)
 	}
 
-	// Validar email
-	if emailErrors := v.validateEmail(contact.Email); len(emailErrors) > 0 {
-		errors = append(errors, emailErrors...)
+	v := &ValidatorService{
+		profile:  profile,
+		optional: make(map[string]bool),
+		registry: NewRuleRegistry(builtins...),
 	}
 
-	// Validar teléfono
-	if phoneErrors := v.validatePhone(contact.Phone); len(phoneErrors) > 0 {
-		errors = append(errors, phoneErrors...)
+	if profile.Required != nil {
+		for _, field := range entities.ContactFields {
+			v.optional[field] = !containsString(profile.Required, field)
+		}
+		for _, field := range profile.Required {
+			if !containsString(entities.ContactFields, field) {
+				return nil, fmt.Errorf("perfil %s: campo obligatorio desconocido: %s", profile.Name, field)
+			}
+		}
 	}
 
-	return errors
+	names := profile.Rules
+	if len(names) == 0 {
+		names = DefaultRuleNames
+	}
+	if v.rules, err = v.registry.Resolve(names); err != nil {
+		return nil, fmt.Errorf("perfil %s: %w", profile.Name, err)
+	}
+	return v, nil
 }
 
-// validateClientKey valida que la clave cliente sea solo números
-func (v *ValidatorService) validateClientKey(clientKey string) []entities.ValidationError {
-	var errors []entities.ValidationError
+// applySeverities cambia la severidad de las reglas indicadas por nombre
+func applySeverities(rules []validation.Rule, severities map[string]string) ([]validation.Rule, error) {
+	applied := 0
+	for i, rule := range rules {
+		severity, ok := severities[rule.Name()]
+		if !ok {
+			continue
+		}
+		overridden, err := WithSeverity(rule, severity)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = overridden
+		applied++
+	}
 
-	if clientKey == "" {
-		errors = append(errors, entities.ValidationError{
-			Field:   "client_key",
-			Value:   clientKey,
-			Message: "La clave cliente no puede estar vacía",
-			Type:    "REQUIRED",
-		})
-		return errors
+	if applied < len(severities) {
+		for name := range severities {
+			if !containsRule(rules, name) {
+				return nil, fmt.Errorf("regla de validación desconocida: %s", name)
+			}
+		}
 	}
+	return rules, nil
+}
 
-	if _, err := strconv.Atoi(clientKey); err != nil {
-		errors = append(errors, entities.ValidationError{
-			Field:   "client_key",
-			Value:   clientKey,
-			Message: "La clave cliente debe contener solo números",
-			Type:    "INVALID_FORMAT",
-		})
+// containsRule indica si la lista incluye una regla con el nombre indicado
+func containsRule(rules []validation.Rule, name string) bool {
+	for _, rule := range rules {
+		if rule.Name() == name {
+			return true
+		}
 	}
+	return false
+}
 
-	return errors
+// LoadProfiles agrega los perfiles de la configuración a los disponibles. Un perfil con
+// el nombre de uno existente lo reemplaza; si la configuración indica un perfil por
+// defecto, se usa cuando no se selecciona otro.
+func (v *ValidatorService) LoadProfiles(config entities.ValidationProfileConfig) error {
+	loaded := make(map[string]*ValidatorService)
+	var names []string
+	for _, profile := range config.Profiles {
+		if _, exists := loaded[profile.Name]; exists {
+			return fmt.Errorf("el perfil %s está repetido", profile.Name)
+		}
+		validator, err := newProfileValidator(profile)
+		if err != nil {
+			return err
+		}
+		loaded[profile.Name] = validator
+		names = append(names, profile.Name)
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for _, name := range names {
+		if _, exists := v.profiles[name]; !exists {
+			v.profileNames = append(v.profileNames, name)
+		}
+		v.profiles[name] = loaded[name]
+	}
+
+	if config.Default != "" {
+		if _, exists := v.profiles[config.Default]; !exists {
+			return fmt.Errorf("el perfil por defecto %s no existe", config.Default)
+		}
+		v.defaultProfile = config.Default
+	}
+	return nil
 }
 
-// validateName valida que el nombre contenga solo letras, espacios y acentos
-func (v *ValidatorService) validateName(name string) []entities.ValidationError {
-	var errors []entities.ValidationError
+// ForProfile obtiene el validador de un perfil; un nombre vacío selecciona el perfil por defecto
+func (v *ValidatorService) ForProfile(name string) (*ValidatorService, error) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
 
+	if v.profiles == nil {
+		return v, nil
+	}
 	if name == "" {
-		errors = append(errors, entities.ValidationError{
-			Field:   "name",
-			Value:   name,
-			Message: "El nombre no puede estar vacío",
-			Type:    "REQUIRED",
-		})
-		return errors
+		name = v.defaultProfile
 	}
 
-	// Permitir letras (incluye acentos), espacios y apostrofes para nombres como "O'Connor"
-	for _, char := range name {
-		if !unicode.IsLetter(char) && !unicode.IsSpace(char) && char != '\'' && char != '.' {
-			errors = append(errors, entities.ValidationError{
-				Field:   "name",
-				Value:   name,
-				Message: "El nombre debe contener solo letras, espacios y apostrofes",
-				Type:    "INVALID_CHARACTER",
-			})
-			break
-		}
+	validator, exists := v.profiles[name]
+	if !exists {
+		return nil, fmt.Errorf("perfil de validación desconocido: %s", name)
 	}
+	return validator, nil
+}
 
-	return errors
+// Profile obtiene el perfil que aplica el validador
+func (v *ValidatorService) Profile() entities.ValidationProfile {
+	return v.profile
+}
+
+// Profiles lista los perfiles disponibles en el orden en que se cargaron
+func (v *ValidatorService) Profiles() ([]entities.ValidationProfile, string) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	profiles := make([]entities.ValidationProfile, 0, len(v.profileNames))
+	for _, name := range v.profileNames {
+		profiles = append(profiles, v.profiles[name].profile)
+	}
+	return profiles, v.defaultProfile
+}
+
+// Registry obtiene el registro de reglas disponibles, donde pueden agregarse reglas nuevas
+func (v *ValidatorService) Registry() *RuleRegistry {
+	return v.registry
+}
+
+// SetRules define la lista ordenada de reglas que ejecuta el validador
+func (v *ValidatorService) SetRules(names []string) error {
+	rules, err := v.registry.Resolve(names)
+	if err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.rules = rules
+	v.version++
+	return nil
 }
 
-// validateEmail valida el formato y dominio del email
-func (v *ValidatorService) validateEmail(email string) []entities.ValidationError {
+// RulesVersion obtiene un número que cambia cada vez que se modifica la lista de reglas
+func (v *ValidatorService) RulesVersion() uint64 {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.version
+}
+
+// Rules obtiene la lista ordenada de reglas que ejecuta el validador
+func (v *ValidatorService) Rules() []validation.Rule {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.rules
+}
+
+// ValidateContact valida todos los campos de un contacto ejecutando las reglas en orden.
+// Cuando un campo no cumple una regla de severidad error se omiten las reglas siguientes
+// de ese campo; las advertencias no detienen la validación. Los campos opcionales vacíos
+// no se validan. El teléfono se analiza una sola vez para todas sus reglas.
+func (v *ValidatorService) ValidateContact(contact *entities.Contact) []entities.ValidationError {
 	var errors []entities.ValidationError
 
-	if email == "" {
-		errors = append(errors, entities.ValidationError{
-			Field:   "email",
-			Value:   email,
-			Message: "El email no puede estar vacío",
-			Type:    "REQUIRED",
-		})
-		return errors
-	}
-
-	// Validar formato básico de email
-	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	if matched, _ := regexp.MatchString(emailRegex, email); !matched {
-		errors = append(errors, entities.ValidationError{
-			Field:   "email",
-			Value:   email,
-			Message: "El formato del email no es válido",
-			Type:    "INVALID_FORMAT",
-		})
-		return errors
-	}
-
-	// Validar dominio conocido
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
-		errors = append(errors, entities.ValidationError{
-			Field:   "email",
-			Value:   email,
-			Message: "El formato del email no es válido",
-			Type:    "INVALID_FORMAT",
-		})
-		return errors
-	}
-
-	domain := strings.ToLower(parts[1])
-	isValidDomain := false
-	for _, validDomain := range v.validEmails {
-		if domain == validDomain {
-			isValidDomain = true
-			break
-		}
-	}
-
-	if !isValidDomain {
-		errors = append(errors, entities.ValidationError{
-			Field:   "email",
-			Value:   email,
-			Message: "El dominio del email no es reconocido (use gmail.com, yahoo.com, hotmail.com, etc.)",
-			Type:    "INVALID_DOMAIN",
-		})
+	phone := parsePhone(contact.Phone)
+	failed := make(map[string]bool)
+	for _, rule := range v.Rules() {
+		if failed[rule.Field()] {
+			continue
+		}
+
+		value := contactField(contact, rule.Field())
+		if value == "" && v.optional[rule.Field()] {
+			continue
+		}
+		if !checkRule(rule, value, phone) {
+			failed[rule.Field()] = rule.Severity() == entities.SeverityError
+			errors = append(errors, ruleError(rule, value))
+		}
+	}
+
+	// Ubicar cada error en la celda del archivo original y proponer correcciones
+	for i := range errors {
+		errors[i].Location = locateField(contact, errors[i].Field)
+		errors[i].Suggestions = v.suggest(errors[i])
 	}
 
 	return errors
 }
 
-// validatePhone valida el formato del teléfono con lada de Chiapas
-func (v *ValidatorService) validatePhone(phone string) []entities.ValidationError {
-	var errors []entities.ValidationError
+// ValidateField valida el valor de un solo campo de contacto igual que ValidateContact:
+// las advertencias se acumulan y el primer error de severidad error detiene la validación
+func (v *ValidatorService) ValidateField(field, value string) []entities.ValidationError {
+	if value == "" && v.optional[field] {
+		return nil
+	}
 
-	if phone == "" {
-		errors = append(errors, entities.ValidationError{
-			Field:   "phone",
-			Value:   phone,
-			Message: "El teléfono no puede estar vacío",
-			Type:    "REQUIRED",
-		})
-		return errors
+	var phone parsedPhone
+	if field == entities.FieldPhone {
+		phone = parsePhone(value)
 	}
 
-	// Remover espacios y caracteres especiales para la validación
-	cleanPhone := regexp.MustCompile(`[^\d]`).ReplaceAllString(phone, "")
+	var errors []entities.ValidationError
+	for _, rule := range v.Rules() {
+		if rule.Field() == field && !checkRule(rule, value, phone) {
+			errors = append(errors, ruleError(rule, value))
+			if rule.Severity() == entities.SeverityError {
+				break
+			}
+		}
+	}
+	return errors
+}
 
-	// Validar que solo contenga números después de limpieza
-	if regexp.MustCompile(`[a-zA-Z]`).MatchString(phone) {
-		errors = append(errors, entities.ValidationError{
-			Field:   "phone",
-			Value:   phone,
-			Message: "El teléfono no debe contener letras",
-			Type:    "INVALID_CHARACTER",
-		})
-		return errors
+// hasErrors indica si alguno de los errores invalida el valor; las advertencias y los
+// avisos informativos no lo invalidan
+func hasErrors(errors []entities.ValidationError) bool {
+	for _, validationError := range errors {
+		if validationError.Severity != entities.SeverityWarning && validationError.Severity != entities.SeverityInfo {
+			return true
+		}
 	}
+	return false
+}
 
-	// Validar longitud (10 dígitos)
-	if len(cleanPhone) != 10 {
-		errors = append(errors, entities.ValidationError{
-			Field:   "phone",
-			Value:   phone,
-			Message: "El teléfono debe tener exactamente 10 dígitos",
-			Type:    "INVALID_LENGTH",
-		})
-		return errors
+// ruleError crea el error de validación de un valor que no cumple la regla
+func ruleError(rule validation.Rule, value string) entities.ValidationError {
+	return entities.ValidationError{
+		Field:    rule.Field(),
+		Value:    value,
+		Message:  rule.Message(),
+		Type:     rule.Code(),
+		Severity: rule.Severity(),
 	}
+}
 
-	// Validar lada de Chiapas
-	lada := cleanPhone[:3]
-	isValidLada := false
-	for _, validLada := range v.chiapasLadas {
-		if lada == validLada {
-			isValidLada = true
-			break
+// DuplicateKeyErrors detecta claves cliente compartidas por varios contactos del conjunto.
+// Retorna el error DUPLICATE_KEY de cada contacto afectado, indexado por su posición.
+func (v *ValidatorService) DuplicateKeyErrors(contacts []*entities.Contact) map[int]entities.ValidationError {
+	byKey := make(map[string][]int)
+	for i, contact := range contacts {
+		if contact.ClientKey != "" {
+			byKey[contact.ClientKey] = append(byKey[contact.ClientKey], i)
 		}
 	}
 
-	if !isValidLada {
-		errors = append(errors, entities.ValidationError{
-			Field:   "phone",
-			Value:   phone,
-			Message: "La lada debe ser de Chiapas (961, 962, 963, 964, 965, 966, 967, 968, 994)",
-			Type:    "INVALID_AREA_CODE",
-		})
+	errors := make(map[int]entities.ValidationError)
+	for key, indexes := range byKey {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		for _, i := range indexes {
+			var related []int
+			var relatedText []string
+			for _, j := range indexes {
+				if j == i || contacts[j].ID == 0 {
+					continue
+				}
+				related = append(related, contacts[j].ID)
+				relatedText = append(relatedText, strconv.Itoa(contacts[j].ID))
+			}
+
+			message := fmt.Sprintf("La clave cliente está repetida en %d contactos", len(indexes))
+			if len(relatedText) > 0 {
+				message = fmt.Sprintf("La clave cliente está repetida en los contactos %s", strings.Join(relatedText, ", "))
+			}
+
+			errors[i] = entities.ValidationError{
+				Field:      "client_key",
+				Value:      key,
+				Message:    message,
+				Type:       "DUPLICATE_KEY",
+				Severity:   entities.SeverityError,
+				Location:   locateField(contacts[i], "client_key"),
+				RelatedIDs: related,
+			}
+		}
 	}
 
 	return errors
-}
\ No newline at end of file
+}
+
+// NewValidationResult crea el resultado de validar un contacto contando sus errores por severidad
+func NewValidationResult(contact *entities.Contact, errors []entities.ValidationError) *entities.ContactWithValidation {
+	result := &entities.ContactWithValidation{
+		Contact: *contact,
+		Errors:  errors,
+	}
+
+	for _, validationError := range errors {
+		switch validationError.Severity {
+		case entities.SeverityWarning:
+			result.WarningCount++
+		case entities.SeverityInfo:
+			result.InfoCount++
+		default:
+			result.ErrorCount++
+		}
+	}
+
+	result.IsValid = result.ErrorCount == 0
+	switch {
+	case !result.IsValid:
+		result.Status = entities.ValidationStatusInvalid
+	case result.WarningCount > 0:
+		result.Status = entities.ValidationStatusValidWithWarnings
+	default:
+		result.Status = entities.ValidationStatusValid
+	}
+	return result
+}
+
+// SummarizeResults calcula las estadísticas de un conjunto de resultados de validación
+func SummarizeResults(results []*entities.ContactWithValidation) entities.ValidationStats {
+	stats := entities.ValidationStats{
+		ErrorsByField:    make(map[string]int),
+		ErrorsByType:     make(map[string]int),
+		ErrorsBySeverity: make(map[string]int),
+	}
+
+	for _, result := range results {
+		stats.Total++
+		if result.IsValid {
+			stats.Valid++
+		} else {
+			stats.Invalid++
+		}
+		if result.Status == entities.ValidationStatusValidWithWarnings {
+			stats.ValidWithWarnings++
+		}
+
+		for _, validationError := range result.Errors {
+			stats.ErrorsByField[validationError.Field]++
+			stats.ErrorsByType[validationError.Type]++
+			stats.ErrorsBySeverity[validationError.Severity]++
+		}
+	}
+
+	return stats
+}
+
+// locateField obtiene la celda del archivo original de la que proviene un campo del contacto
+func locateField(contact *entities.Contact, field string) *entities.CellLocation {
+	if contact.Source == nil {
+		return nil
+	}
+
+	column, ok := contact.Source.Columns[field]
+	if !ok {
+		return nil
+	}
+
+	return &entities.CellLocation{
+		FileName: contact.Source.FileName,
+		Sheet:    contact.Source.Sheet,
+		Row:      contact.Source.Row,
+		Column:   column,
+		Cell:     fmt.Sprintf("%s%d", column, contact.Source.Row),
+	}
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+func TestDuplicateKeyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contacts []*entities.Contact
+		// related son los IDs relacionados del error de cada posición con clave repetida
+		related map[int][]int
+	}{
+		{
+			name:     "claves distintas",
+			contacts: []*entities.Contact{{ID: 1, ClientKey: "1"}, {ID: 2, ClientKey: "2"}},
+			related:  map[int][]int{},
+		},
+		{
+			name:     "claves vacías no se repiten",
+			contacts: []*entities.Contact{{ID: 1}, {ID: 2}},
+			related:  map[int][]int{},
+		},
+		{
+			name:     "clave en tres contactos",
+			contacts: []*entities.Contact{{ID: 1, ClientKey: "1"}, {ID: 2, ClientKey: "2"}, {ID: 3, ClientKey: "1"}, {ID: 4, ClientKey: "1"}},
+			related:  map[int][]int{0: {3, 4}, 2: {1, 4}, 3: {1, 3}},
+		},
+		{
+			name:     "contactos sin guardar",
+			contacts: []*entities.Contact{{ClientKey: "1"}, {ClientKey: "1"}},
+			related:  map[int][]int{0: nil, 1: nil},
+		},
+	}
+
+	validator := NewValidatorService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.DuplicateKeyErrors(tt.contacts)
+			if len(errors) != len(tt.related) {
+				t.Fatalf("DuplicateKeyErrors() retornó %d errores, se esperaban %d", len(errors), len(tt.related))
+			}
+			for i, related := range tt.related {
+				err, exists := errors[i]
+				if !exists || err.Type != "DUPLICATE_KEY" || err.Severity != entities.SeverityError {
+					t.Fatalf("posición %d: error = %+v, se esperaba DUPLICATE_KEY", i, err)
+				}
+				if !reflect.DeepEqual(err.RelatedIDs, related) {
+					t.Errorf("posición %d: RelatedIDs = %v, se esperaba %v", i, err.RelatedIDs, related)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile entities.ValidationProfile
+		err     string
+		// contact se valida con el perfil cargado y errors son los tipos de error esperados
+		contact entities.Contact
+		errors  []string
+	}{
+		{
+			name:    "campos opcionales",
+			profile: entities.ValidationProfile{Name: "p", Required: []string{entities.FieldClientKey, entities.FieldName}},
+			contact: entities.Contact{ClientKey: "1", Name: "Ana"},
+		},
+		{
+			name:    "campos obligatorios por defecto",
+			profile: entities.ValidationProfile{Name: "p"},
+			contact: entities.Contact{ClientKey: "1", Name: "Ana"},
+			errors:  []string{"REQUIRED", "REQUIRED"},
+		},
+		{
+			name: "severidad cambiada",
+			profile: entities.ValidationProfile{
+				Name:       "p",
+				Required:   []string{entities.FieldEmail},
+				Severities: map[string]string{"email.domain": entities.SeverityError},
+				Email:      entities.EmailPolicy{AllowedDomains: []string{"empresa.com.mx"}},
+			},
+			contact: entities.Contact{Email: "ana@gmail.com"},
+			errors:  []string{"INVALID_DOMAIN"},
+		},
+		{
+			name:    "ladas del perfil",
+			profile: entities.ValidationProfile{Name: "p", Required: []string{entities.FieldPhone}, Phone: entities.PhonePolicy{AreaCodes: []string{"961"}}},
+			contact: entities.Contact{Phone: "5583527411"},
+			errors:  []string{"INVALID_AREA_CODE"},
+		},
+		{
+			name:    "perfil sin nombre",
+			profile: entities.ValidationProfile{},
+			err:     "debe tener nombre",
+		},
+		{
+			name:    "campo obligatorio desconocido",
+			profile: entities.ValidationProfile{Name: "p", Required: []string{"fax"}},
+			err:     "campo obligatorio desconocido",
+		},
+		{
+			name:    "severidad de una regla desconocida",
+			profile: entities.ValidationProfile{Name: "p", Severities: map[string]string{"fax.format": entities.SeverityError}},
+			err:     "regla de validación desconocida",
+		},
+		{
+			name:    "regla desconocida",
+			profile: entities.ValidationProfile{Name: "p", Rules: []string{"fax.format"}},
+			err:     "fax.format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Los perfiles de prueba usan las clases de caracteres del perfil por defecto
+			profile := tt.profile
+			profile.Names = DefaultValidationProfile().Names
+			validator := NewValidatorService()
+			err := validator.LoadProfiles(entities.ValidationProfileConfig{Profiles: []entities.ValidationProfile{profile}})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("LoadProfiles() error = %v, se esperaba %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProfiles() error = %v", err)
+			}
+
+			profiled, err := validator.ForProfile(tt.profile.Name)
+			if err != nil {
+				t.Fatalf("ForProfile() error = %v", err)
+			}
+			var types []string
+			for _, validationError := range profiled.ValidateContact(&tt.contact) {
+				if validationError.Severity == entities.SeverityError {
+					types = append(types, validationError.Type)
+				}
+			}
+			if !reflect.DeepEqual(types, tt.errors) {
+				t.Errorf("ValidateContact() = %v, se esperaba %v", types, tt.errors)
+			}
+		})
+	}
+}
+
+func TestForProfile(t *testing.T) {
+	validator := NewValidatorService()
+	config := entities.ValidationProfileConfig{
+		Default:  "nacional",
+		Profiles: []entities.ValidationProfile{{Name: "nacional"}, {Name: "corporativo"}},
+	}
+	for i := range config.Profiles {
+		config.Profiles[i].Names = DefaultValidationProfile().Names
+	}
+	if err := validator.LoadProfiles(config); err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    string
+		err     bool
+	}{
+		{name: "perfil por defecto", profile: "", want: "nacional"},
+		{name: "perfil incluido", profile: "chiapas", want: "chiapas"},
+		{name: "perfil cargado", profile: "corporativo", want: "corporativo"},
+		{name: "perfil desconocido", profile: "otro", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiled, err := validator.ForProfile(tt.profile)
+			if (err != nil) != tt.err {
+				t.Fatalf("ForProfile(%q) error = %v", tt.profile, err)
+			}
+			if err == nil && profiled.profile.Name != tt.want {
+				t.Errorf("ForProfile(%q) = %s, se esperaba %s", tt.profile, profiled.profile.Name, tt.want)
+			}
+		})
+	}
+
+	if err := validator.LoadProfiles(entities.ValidationProfileConfig{Default: "otro", Profiles: config.Profiles[:1]}); err == nil {
+		t.Errorf("LoadProfiles() con un perfil por defecto inexistente no retornó error")
+	}
+	if err := validator.LoadProfiles(entities.ValidationProfileConfig{Profiles: []entities.ValidationProfile{config.Profiles[0], config.Profiles[0]}}); err == nil {
+		t.Errorf("LoadProfiles() con un perfil repetido no retornó error")
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// Parámetros para detectar claves cliente que perdieron sus ceros a la izquierda
+const (
+	// minKeyWidthShare es la proporción mínima de claves con la misma longitud para
+	// considerarla la longitud del dataset
+	minKeyWidthShare = 0.8
+	// maxDomainDistance es la distancia de edición máxima para sugerir un dominio conocido
+	maxDomainDistance = 2
+)
+
+var (
+	nonDigitRegex = regexp.MustCompile(`[^\d]`)
+	// excelNumberRegex reconoce claves numéricas convertidas a decimal por la hoja de cálculo (123.0)
+	excelNumberRegex = regexp.MustCompile(`^(\d+)\.0+$`)
+)
+
+// suggest propone valores corregidos para un error de validación. Solo se sugieren
+// valores que pasan la validación de su campo.
+func (v *ValidatorService) suggest(validationError entities.ValidationError) []string {
+	var candidates []string
+	switch validationError.Field {
+	case entities.FieldClientKey:
+		candidates = v.suggestClientKey(validationError.Value)
+	case entities.FieldName:
+		candidates = v.suggestName(validationError.Value)
+	case entities.FieldEmail:
+		candidates = v.suggestEmail(validationError.Value)
+	case entities.FieldPhone:
+		candidates = v.suggestPhone(validationError.Value)
+	}
+
+	var suggestions []string
+	seen := map[string]bool{validationError.Value: true}
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		if len(v.ValidateField(validationError.Field, candidate)) == 0 {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// suggestClientKey propone la clave sin el decimal agregado por la hoja de cálculo
+// o sin los caracteres que no son dígitos
+func (v *ValidatorService) suggestClientKey(clientKey string) []string {
+	trimmed := strings.TrimSpace(clientKey)
+	if match := excelNumberRegex.FindStringSubmatch(trimmed); match != nil {
+		return []string{match[1]}
+	}
+	if digits := nonDigitRegex.ReplaceAllString(trimmed, ""); digits != "" {
+		return []string{digits}
+	}
+	return nil
+}
+
+// suggestName propone el nombre sin los caracteres que no permiten las clases del perfil
+// y sin espacios repetidos
+func (v *ValidatorService) suggestName(name string) []string {
+	classes := v.profile.Names.CharacterClasses
+	cleaned := strings.Map(func(char rune) rune {
+		if allowsNameCharacter(classes, char) {
+			return char
+		}
+		return ' '
+	}, name)
+
+	separator := ""
+	if allowsNameCharacter(classes, ' ') {
+		separator = " "
+	}
+	cleaned = strings.Join(strings.Fields(cleaned), separator)
+	if cleaned == "" {
+		return nil
+	}
+	return []string{cleaned}
+}
+
+// suggestEmail propone el email sin espacios, en minúsculas y con el dominio conocido
+// más parecido cuando el dominio tiene un error de escritura o le falta la extensión
+func (v *ValidatorService) suggestEmail(email string) []string {
+	cleaned := strings.ToLower(strings.Join(strings.Fields(email), ""))
+	cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	cleaned = strings.Trim(cleaned, ".")
+
+	candidates := []string{cleaned}
+
+	parts := strings.Split(cleaned, "@")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return candidates
+	}
+	local, domain := parts[0], parts[1]
+
+	type domainMatch struct {
+		domain   string
+		distance int
+	}
+	// Sin dominios permitidos en el perfil se compara contra los dominios personales comunes
+	knownDomains := v.profile.Email.AllowedDomains
+	if len(knownDomains) == 0 {
+		knownDomains = DefaultValidationProfile().Email.AllowedDomains
+	}
+
+	var matches []domainMatch
+	for _, validDomain := range lowerAll(knownDomains) {
+		distance := levenshtein(domain, validDomain)
+		if name := strings.SplitN(validDomain, ".", 2)[0]; domain == name {
+			distance = 1
+		}
+		if distance <= maxDomainDistance {
+			matches = append(matches, domainMatch{validDomain, distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+	for _, match := range matches {
+		candidates = append(candidates, local+"@"+match.domain)
+	}
+	return candidates
+}
+
+// suggestPhone propone los números de México con la longitud del perfil sin el código de
+// país ni los prefijos de marcación nacionales, y los de otros países en formato E.164,
+// también cuando se escribieron sin + ni 00
+func (v *ValidatorService) suggestPhone(phone string) []string {
+	parsed := parsePhone(phone)
+	if parsed.Country != defaultPhoneCountry {
+		return internationalSuggestion(parsed)
+	}
+
+	digits := nonDigitRegex.ReplaceAllString(extensionRegex.ReplaceAllString(phone, ""), "")
+	length := v.profile.Phone.Length
+	for _, prefix := range nationalPrefixes {
+		if len(digits) == len(prefix)+length && strings.HasPrefix(digits, prefix) {
+			return []string{withExtension(digits[len(prefix):], parsed.Extension)}
+		}
+	}
+	if len(digits) == length {
+		return []string{withExtension(digits, parsed.Extension)}
+	}
+
+	if !parsed.International {
+		foreign := parsePhone("+" + digits)
+		foreign.Extension = parsed.Extension
+		if foreign.Country != defaultPhoneCountry {
+			return internationalSuggestion(foreign)
+		}
+	}
+	return nil
+}
+
+// internationalSuggestion propone un número de otro país en formato E.164
+func internationalSuggestion(parsed parsedPhone) []string {
+	if parsed.Country == "" || !parsed.ValidLength() {
+		return nil
+	}
+	return []string{withExtension(parsed.E164(), parsed.Extension)}
+}
+
+// withExtension agrega la extensión al número sugerido para no perderla
+func withExtension(number, extension string) string {
+	if extension == "" {
+		return number
+	}
+	return number + " ext. " + extension
+}
+
+// ClientKeyLengthErrors detecta claves cliente numéricas más cortas que la longitud que
+// comparten casi todas las claves del conjunto, como ocurre cuando la hoja de cálculo
+// elimina los ceros a la izquierda. Retorna la advertencia de cada contacto afectado, indexada
+// por su posición, con la clave completada con ceros como sugerencia. Las claves que ya
+// no cumplen las reglas del campo se omiten para reportar un solo error por campo.
+func (v *ValidatorService) ClientKeyLengthErrors(contacts []*entities.Contact) map[int]entities.ValidationError {
+	lengths := make(map[int]int)
+	numeric := 0
+	for _, contact := range contacts {
+		if isDigits(contact.ClientKey) {
+			lengths[len(contact.ClientKey)]++
+			numeric++
+		}
+	}
+
+	errors := make(map[int]entities.ValidationError)
+	width, exists := keyWidth(lengths, numeric)
+	if !exists {
+		return errors
+	}
+
+	for i, contact := range contacts {
+		key := contact.ClientKey
+		if !isDigits(key) || len(key) >= width || len(v.ValidateField(entities.FieldClientKey, key)) > 0 {
+			continue
+		}
+
+		padded := strings.Repeat("0", width-len(key)) + key
+		var suggestions []string
+		if len(v.ValidateField(entities.FieldClientKey, padded)) == 0 {
+			suggestions = []string{padded}
+		}
+		errors[i] = entities.ValidationError{
+			Field:       entities.FieldClientKey,
+			Value:       key,
+			Message:     fmt.Sprintf("La clave cliente tiene %d dígitos; las demás claves tienen %d", len(key), width),
+			Type:        "MISSING_LEADING_ZEROS",
+			Severity:    entities.SeverityWarning,
+			Location:    locateField(contact, entities.FieldClientKey),
+			Suggestions: suggestions,
+		}
+	}
+	return errors
+}
+
+// keyWidth obtiene la longitud que comparten casi todas las claves numéricas a partir de
+// cuántas claves hay de cada longitud; no existe si ninguna longitud alcanza minKeyWidthShare
+func keyWidth(lengths map[int]int, numeric int) (int, bool) {
+	width, count := 0, 0
+	for length, n := range lengths {
+		if n > count || (n == count && length > width) {
+			width, count = length, n
+		}
+	}
+	return width, numeric >= 2 && float64(count) >= minKeyWidthShare*float64(numeric)
+}
+
+// isDigits indica si el texto no está vacío y contiene solo dígitos
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, char := range text {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
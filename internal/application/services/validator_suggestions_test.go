@@ -0,0 +1,114 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// nationalValidator obtiene un validador con el perfil por defecto sin restricción de
+// ladas, que acepta teléfonos de cualquier lada del plan y de otros países
+func nationalValidator(t *testing.T) *ValidatorService {
+	t.Helper()
+	profile := DefaultValidationProfile()
+	profile.Name = "nacional"
+	profile.Phone = entities.PhonePolicy{Length: 10}
+
+	validator := NewValidatorService()
+	config := entities.ValidationProfileConfig{Default: profile.Name, Profiles: []entities.ValidationProfile{profile}}
+	if err := validator.LoadProfiles(config); err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+	return validator
+}
+
+func TestValidationSuggestions(t *testing.T) {
+	tests := []struct {
+		field       string
+		value       string
+		errorType   string
+		suggestions []string
+	}{
+		{field: entities.FieldClientKey, value: "123.0", errorType: "INVALID_FORMAT", suggestions: []string{"123"}},
+		{field: entities.FieldClientKey, value: "12-34", errorType: "INVALID_FORMAT", suggestions: []string{"1234"}},
+		{field: entities.FieldClientKey, value: "abc", errorType: "INVALID_FORMAT"},
+		{field: entities.FieldName, value: "Ana3 Ruiz", errorType: "INVALID_CHARACTER", suggestions: []string{"Ana Ruiz"}},
+		{field: entities.FieldName, value: "Ana_Ruiz", errorType: "INVALID_CHARACTER", suggestions: []string{"Ana Ruiz"}},
+		{field: entities.FieldName, value: "123", errorType: "INVALID_CHARACTER"},
+		{field: entities.FieldEmail, value: "ANA@GMAIL.COM ", errorType: "INVALID_FORMAT", suggestions: []string{"ana@gmail.com"}},
+		{field: entities.FieldEmail, value: "ana@gmail,com", errorType: "INVALID_FORMAT", suggestions: []string{"ana@gmail.com"}},
+		{field: entities.FieldEmail, value: "ana@hotmail", errorType: "INVALID_FORMAT", suggestions: []string{"ana@hotmail.com"}},
+		{field: entities.FieldEmail, value: "ana@gmial.com", errorType: "INVALID_DOMAIN", suggestions: []string{"ana@gmail.com"}},
+		{field: entities.FieldEmail, value: "ana@empresa.com", errorType: "INVALID_DOMAIN"},
+		{field: entities.FieldPhone, value: "961835274", errorType: "INVALID_LENGTH"},
+		{field: entities.FieldPhone, value: "34612935278", errorType: "INVALID_LENGTH", suggestions: []string{"+34612935278"}},
+		{field: entities.FieldPhone, value: "34 612 935 278 ext 5", errorType: "INVALID_LENGTH", suggestions: []string{"+34612935278 ext. 5"}},
+	}
+
+	validator, err := nationalValidator(t).ForProfile("")
+	if err != nil {
+		t.Fatalf("ForProfile() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.field+" "+tt.value, func(t *testing.T) {
+			contact := &entities.Contact{ClientKey: "1", Name: "Ana", Email: "ana@gmail.com", Phone: "9618352741"}
+			setContactField(contact, tt.field, tt.value)
+
+			errors := validator.ValidateContact(contact)
+			if len(errors) != 1 || errors[0].Field != tt.field || errors[0].Type != tt.errorType {
+				t.Fatalf("ValidateContact() = %+v, se esperaba un error %s en %s", errors, tt.errorType, tt.field)
+			}
+			if !reflect.DeepEqual(errors[0].Suggestions, tt.suggestions) {
+				t.Errorf("Suggestions = %q, se esperaba %q", errors[0].Suggestions, tt.suggestions)
+			}
+		})
+	}
+}
+
+func TestClientKeyLengthErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		// padded es la clave sugerida de cada posición con advertencia
+		padded map[int]string
+	}{
+		{
+			name:   "claves sin ceros a la izquierda",
+			keys:   []string{"00012", "00345", "06789", "12", "00001", "00002", "00003", "00004", "00005", "00006"},
+			padded: map[int]string{3: "00012"},
+		},
+		{
+			name:   "longitudes mezcladas",
+			keys:   []string{"1", "22", "333", "4444"},
+			padded: map[int]string{},
+		},
+		{
+			name:   "claves no numéricas",
+			keys:   []string{"A0001", "A0002", "12"},
+			padded: map[int]string{},
+		},
+	}
+
+	validator := NewValidatorService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts := make([]*entities.Contact, len(tt.keys))
+			for i, key := range tt.keys {
+				contacts[i] = &entities.Contact{ClientKey: key}
+			}
+
+			errors := validator.ClientKeyLengthErrors(contacts)
+			padded := make(map[int]string, len(errors))
+			for i, err := range errors {
+				if err.Type != "MISSING_LEADING_ZEROS" || err.Severity != entities.SeverityWarning || len(err.Suggestions) != 1 {
+					t.Fatalf("posición %d: error = %+v, se esperaba una advertencia con sugerencia", i, err)
+				}
+				padded[i] = err.Suggestions[0]
+			}
+			if !reflect.DeepEqual(padded, tt.padded) {
+				t.Errorf("ClientKeyLengthErrors() sugiere %v, se esperaba %v", padded, tt.padded)
+			}
+		})
+	}
+}
@@ -4,26 +4,122 @@ import "time"
 
 // Contact representa una entidad de contacto del dominio
 type Contact struct {
-	ID           int       `json:"id"`
-	ClientKey    string    `json:"client_key"`
-	Name         string    `json:"name"`
-	Email        string    `json:"email"`
-	Phone        string    `json:"phone"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             int            `json:"id"`
+	DatasetID      string         `json:"dataset_id"`
+	ClientKey      string         `json:"client_key"`
+	Name           string         `json:"name"`
+	Email          string         `json:"email"`
+	Phone          string         `json:"phone"`
+	PhoneE164      string         `json:"phone_e164,omitempty"`
+	PhoneExtension string         `json:"phone_extension,omitempty"`
+	PhoneLocation  *PhoneLocation `json:"phone_location,omitempty"`
+	Status         string         `json:"status,omitempty"`
+	Source         *ContactSource `json:"source,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
+// ContactStatusMissing marca un contacto que no apareció en la última carga en modo upsert
+const ContactStatusMissing = "MISSING"
+
+// PhoneLocation representa la ubicación de un teléfono según su lada en el plan de numeración.
+// Una lada suele cubrir varios municipios; solo se registra la ciudad principal de la lada,
+// no el municipio del número.
+type PhoneLocation struct {
+	AreaCode string `json:"area_code"`
+	State    string `json:"state"`
+	MainCity string `json:"main_city,omitempty"`
+}
+
+// ContactSource representa el origen de un contacto dentro del archivo importado
+type ContactSource struct {
+	UploadID string            `json:"upload_id"`
+	FileName string            `json:"file_name"`
+	Sheet    string            `json:"sheet"`
+	Row      int               `json:"row"`
+	Columns  map[string]string `json:"columns"`
+}
+
+// CellLocation representa la celda del archivo original donde se encuentra un valor
+type CellLocation struct {
+	FileName string `json:"file_name"`
+	Sheet    string `json:"sheet"`
+	Row      int    `json:"row"`
+	Column   string `json:"column"`
+	Cell     string `json:"cell"`
+}
+
+// Niveles de severidad de una regla de validación. Solo los errores invalidan un contacto.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Estado de un contacto después de validarlo
+const (
+	ValidationStatusValid             = "VALID"
+	ValidationStatusValidWithWarnings = "VALID_WITH_WARNINGS"
+	ValidationStatusInvalid           = "INVALID"
+)
+
 // ValidationError representa un error de validación
 type ValidationError struct {
-	Field   string `json:"field"`
-	Value   string `json:"value"`
-	Message string `json:"message"`
-	Type    string `json:"type"`
+	Field       string        `json:"field"`
+	Value       string        `json:"value"`
+	Message     string        `json:"message"`
+	Type        string        `json:"type"`
+	Severity    string        `json:"severity"`
+	Location    *CellLocation `json:"location,omitempty"`
+	RelatedIDs  []int         `json:"related_ids,omitempty"`
+	Suggestions []string      `json:"suggestions,omitempty"`
 }
 
-// ContactWithValidation representa un contacto con sus errores de validación
+// ContactWithValidation representa un contacto con sus errores de validación de todas
+// las severidades. Un contacto es válido si no tiene errores de severidad error.
 type ContactWithValidation struct {
-	Contact Contact           `json:"contact"`
-	Errors  []ValidationError `json:"errors"`
-	IsValid bool              `json:"is_valid"`
-}
\ No newline at end of file
+	Contact      Contact           `json:"contact"`
+	Errors       []ValidationError `json:"errors"`
+	IsValid      bool              `json:"is_valid"`
+	Status       string            `json:"status"`
+	ErrorCount   int               `json:"error_count"`
+	WarningCount int               `json:"warning_count"`
+	InfoCount    int               `json:"info_count"`
+}
+
+// Filtros de validez de los resultados de validación
+const (
+	ValidityValid    = "valid"
+	ValidityInvalid  = "invalid"
+	ValidityWarnings = "warnings"
+)
+
+// ValidationFilter selecciona resultados de validación: por validez, por campo y tipo de
+// error (ambos en un mismo error) y por texto libre en los campos del contacto. Los
+// criterios vacíos no filtran.
+type ValidationFilter struct {
+	Validity string `json:"validity,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// ValidationStats resume los resultados de validar un conjunto de contactos. Valid
+// incluye a los contactos válidos con advertencias, que también se cuentan aparte.
+type ValidationStats struct {
+	Total             int            `json:"total"`
+	Valid             int            `json:"valid"`
+	ValidWithWarnings int            `json:"valid_with_warnings"`
+	Invalid           int            `json:"invalid"`
+	ErrorsByField     map[string]int `json:"errors_by_field"`
+	ErrorsByType      map[string]int `json:"errors_by_type"`
+	ErrorsBySeverity  map[string]int `json:"errors_by_severity"`
+}
+
+// DuplicateCluster representa un grupo de contactos que probablemente son la misma persona
+type DuplicateCluster struct {
+	ContactIDs    []int      `json:"contact_ids"`
+	Contacts      []*Contact `json:"contacts"`
+	Score         float64    `json:"score"`
+	MatchedFields []string   `json:"matched_fields"`
+}
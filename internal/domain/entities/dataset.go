@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// Dataset representa un conjunto de contactos creado a partir de una carga
+type Dataset struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	SourceFile   string    `json:"source_file"`
+	Profile      string    `json:"profile,omitempty"`
+	ContactCount int       `json:"contact_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
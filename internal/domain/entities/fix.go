@@ -0,0 +1,41 @@
+package entities
+
+// Reglas que producen una corrección propuesta
+const (
+	FixRuleTrimWhitespace = "trim_whitespace"
+	FixRuleLowercaseEmail = "lowercase_email"
+	FixRuleNormalizePhone = "normalize_phone"
+	FixRuleSuggestion     = "suggestion"
+)
+
+// ContactFix representa una corrección propuesta para un campo de un contacto inválido
+type ContactFix struct {
+	ContactID int    `json:"contact_id"`
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	Rule      string `json:"rule"`
+	ErrorType string `json:"error_type"`
+}
+
+// FixSelection identifica una corrección aceptada por el operador con los valores que
+// revisó; si la corrección propuesta ya no coincide no se aplica
+type FixSelection struct {
+	ContactID int    `json:"contact_id"`
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+}
+
+// ApplyFixesRequest representa la solicitud para aplicar todas las correcciones
+// propuestas o solo las aceptadas
+type ApplyFixesRequest struct {
+	All   bool           `json:"all"`
+	Fixes []FixSelection `json:"fixes"`
+}
+
+// FixResult resume las correcciones aplicadas a un dataset
+type FixResult struct {
+	Applied  []ContactFix `json:"applied"`
+	Contacts int          `json:"contacts"`
+}
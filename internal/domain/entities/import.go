@@ -0,0 +1,183 @@
+package entities
+
+import "time"
+
+// Campos de Contact que pueden importarse desde un archivo
+const (
+	FieldClientKey = "client_key"
+	FieldName      = "name"
+	FieldEmail     = "email"
+	FieldPhone     = "phone"
+)
+
+// ContactFields lista los campos importables en su orden posicional por defecto (A–D)
+var ContactFields = []string{FieldClientKey, FieldName, FieldEmail, FieldPhone}
+
+// Origen de una asignación de columna
+const (
+	MappingSourceDetected = "detected"
+	MappingSourceExplicit = "explicit"
+	MappingSourceDefault  = "default"
+)
+
+// ColumnMapping representa la asignación de una columna del archivo a un campo de Contact
+type ColumnMapping struct {
+	Field  string  `json:"field"`
+	Header string  `json:"header"`
+	Column string  `json:"column"`
+	Index  int     `json:"index"`
+	Score  float64 `json:"score"`
+	Source string  `json:"source"`
+}
+
+// Motivos por los que una fila se omite durante la importación
+const (
+	SkipReasonTooFewCells    = "TOO_FEW_CELLS"
+	SkipReasonBlankRow       = "BLANK_ROW"
+	SkipReasonUnparsableCell = "UNPARSABLE_CELL"
+)
+
+// SkippedRow representa una fila de la hoja que no se importó
+type SkippedRow struct {
+	Sheet  string   `json:"sheet"`
+	Row    int      `json:"row"`
+	Reason string   `json:"reason"`
+	Detail string   `json:"detail"`
+	Values []string `json:"values"`
+}
+
+// ImportReport resume el resultado de importar las filas de una hoja
+type ImportReport struct {
+	RowsRead    int          `json:"rows_read"`
+	Imported    int          `json:"imported"`
+	Skipped     int          `json:"skipped"`
+	SkippedRows []SkippedRow `json:"skipped_rows"`
+}
+
+// SheetInfo describe una hoja del libro antes de importarla
+type SheetInfo struct {
+	Index   int             `json:"index"`
+	Name    string          `json:"name"`
+	Rows    int             `json:"rows"`
+	Headers []string        `json:"headers"`
+	Mapping []ColumnMapping `json:"mapping"`
+	Matches bool            `json:"matches"`
+}
+
+// SheetImport representa el resultado de importar una hoja
+type SheetImport struct {
+	Sheet   string          `json:"sheet"`
+	Mapping []ColumnMapping `json:"mapping"`
+	Report  *ImportReport   `json:"report"`
+}
+
+// SkippedSheet representa una hoja que no se importó
+type SkippedSheet struct {
+	Sheet  string `json:"sheet"`
+	Reason string `json:"reason"`
+}
+
+// FileFormat describe el formato detectado de un archivo importado
+type FileFormat struct {
+	Name      string `json:"name"`
+	Delimiter string `json:"delimiter,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
+}
+
+// Modos de importación
+const (
+	ImportModeAppend = "append"
+	ImportModeUpsert = "upsert"
+)
+
+// Tratamiento de los contactos del dataset que no aparecen en una carga en modo upsert
+const (
+	MissingPolicyKeep   = "keep"
+	MissingPolicyMark   = "mark"
+	MissingPolicyRemove = "remove"
+)
+
+// Resultado de aplicar una fila al dataset
+const (
+	RowOutcomeInserted  = "INSERTED"
+	RowOutcomeUpdated   = "UPDATED"
+	RowOutcomeUnchanged = "UNCHANGED"
+	RowOutcomeConflict  = "CONFLICT"
+)
+
+// ImportOptions representa las opciones de una carga: hoja a importar, mapeo explícito
+// de columnas, modo de importación, tratamiento de los contactos ausentes y perfil de
+// validación (para la vista previa y para el dataset que crea la carga)
+type ImportOptions struct {
+	Sheet   string            `json:"sheet"`
+	Mapping map[string]string `json:"mapping"`
+	Mode    string            `json:"mode"`
+	Missing string            `json:"missing"`
+	Profile string            `json:"profile,omitempty"`
+}
+
+// RowOutcome representa cómo se aplicó una fila del archivo al dataset
+type RowOutcome struct {
+	Sheet     string `json:"sheet"`
+	Row       int    `json:"row"`
+	ClientKey string `json:"client_key"`
+	Outcome   string `json:"outcome"`
+	ContactID int    `json:"contact_id,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// ImportOutcome resume cómo se aplicó una carga al dataset. Rows solo lista las filas en
+// conflicto, que no se aplicaron; las demás se cuentan en los totales.
+type ImportOutcome struct {
+	Mode          string       `json:"mode"`
+	Inserted      int          `json:"inserted"`
+	Updated       int          `json:"updated"`
+	Unchanged     int          `json:"unchanged"`
+	Conflicts     int          `json:"conflicts"`
+	MarkedMissing int          `json:"marked_missing"`
+	Removed       int          `json:"removed"`
+	Rows          []RowOutcome `json:"rows"`
+}
+
+// ImportCommit representa una carga guardada en un dataset. Validation son las
+// estadísticas de validación de todo el dataset después de la carga.
+type ImportCommit struct {
+	Dataset    *Dataset         `json:"dataset"`
+	Result     *ImportResult    `json:"result"`
+	Outcome    *ImportOutcome   `json:"outcome"`
+	Validation *ValidationStats `json:"validation,omitempty"`
+}
+
+// ImportResult describe lo que se leyó de un archivo, antes o después de guardarse
+type ImportResult struct {
+	UploadID      string         `json:"upload_id"`
+	FileName      string         `json:"file_name"`
+	Format        FileFormat     `json:"format"`
+	Options       ImportOptions  `json:"options"`
+	Report        *ImportReport  `json:"report"`
+	Sheets        []*SheetImport `json:"sheets"`
+	SkippedSheets []SkippedSheet `json:"skipped_sheets"`
+}
+
+// StagedImport representa una carga validada en modo de vista previa, pendiente de confirmar
+// o descartar. Sus contactos esperan en el dataset provisional PendingID, que no aparece en
+// la lista de datasets.
+type StagedImport struct {
+	Token       string        `json:"token"`
+	DatasetID   string        `json:"dataset_id"`
+	DatasetName string        `json:"dataset_name"`
+	PendingID   string        `json:"-"`
+	Result      *ImportResult `json:"result"`
+	CreatedAt   time.Time     `json:"created_at"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+}
+
+// ImportPreview representa la vista previa de una carga: los primeros contactos con sus
+// errores de validación, el mapeo de columnas y las estadísticas de todo el archivo
+type ImportPreview struct {
+	Token     string                   `json:"token"`
+	ExpiresAt time.Time                `json:"expires_at"`
+	Result    *ImportResult            `json:"result"`
+	Stats     ValidationStats          `json:"stats"`
+	Contacts  []*ContactWithValidation `json:"contacts"`
+}
@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// Estados de un trabajo de importación
+const (
+	ImportJobPending   = "PENDING"
+	ImportJobRunning   = "RUNNING"
+	ImportJobCompleted = "COMPLETED"
+	ImportJobFailed    = "FAILED"
+	ImportJobCancelled = "CANCELLED"
+)
+
+// ImportJob representa una carga que se procesa en segundo plano. TotalRows se conoce al
+// empezar si el archivo indica el tamaño de sus hojas y, si no, al terminar de leerlo;
+// Progress es el porcentaje procesado, estimado por las filas o por los bytes leídos.
+// Report resume el resultado cuando el trabajo termina correctamente y Errors contiene los
+// errores que lo detuvieron o las hojas omitidas.
+type ImportJob struct {
+	ID              string           `json:"id"`
+	Status          string           `json:"status"`
+	DatasetID       string           `json:"dataset_id,omitempty"`
+	FileName        string           `json:"file_name"`
+	Options         ImportOptions    `json:"options"`
+	TotalRows       int              `json:"total_rows"`
+	RowsProcessed   int              `json:"rows_processed"`
+	Progress        int              `json:"progress"`
+	Imported        int              `json:"imported"`
+	Skipped         int              `json:"skipped"`
+	Errors          []string         `json:"errors"`
+	Report          *ImportJobReport `json:"report,omitempty"`
+	CancelRequested bool             `json:"cancel_requested"`
+	CreatedAt       time.Time        `json:"created_at"`
+	StartedAt       *time.Time       `json:"started_at,omitempty"`
+	FinishedAt      *time.Time       `json:"finished_at,omitempty"`
+	ExpiresAt       *time.Time       `json:"-"`
+}
+
+// ImportJobReport resume el resultado de un trabajo de importación terminado. Un trabajo se
+// conserva por horas, así que solo guarda las primeras filas omitidas y en conflicto; los
+// totales de Report, de cada hoja y de Outcome cuentan todas las filas.
+type ImportJobReport struct {
+	UploadID      string           `json:"upload_id"`
+	Format        FileFormat       `json:"format"`
+	Report        *ImportReport    `json:"report"`
+	Sheets        []*SheetImport   `json:"sheets"`
+	SkippedSheets []SkippedSheet   `json:"skipped_sheets"`
+	Outcome       *ImportOutcome   `json:"outcome"`
+	Validation    *ValidationStats `json:"validation,omitempty"`
+}
+
+// Finished indica si el trabajo ya terminó, correctamente o no
+func (j *ImportJob) Finished() bool {
+	return j.Status == ImportJobCompleted || j.Status == ImportJobFailed || j.Status == ImportJobCancelled
+}
@@ -0,0 +1,47 @@
+package entities
+
+import "time"
+
+// Reglas de supervivencia para elegir el valor de un campo al fusionar contactos
+const (
+	MergeRuleSurvivor   = "survivor"
+	MergeRuleMostRecent = "most_recent"
+	MergeRuleLongest    = "longest"
+	MergeRuleFirstValid = "first_valid"
+	MergeRuleSource     = "source"
+)
+
+// FieldMergeRule indica de dónde tomar el valor de un campo: un contacto específico o una regla
+type FieldMergeRule struct {
+	SourceID int    `json:"source_id"`
+	Rule     string `json:"rule"`
+}
+
+// MergeRequest representa una solicitud para fusionar contactos duplicados en un sobreviviente
+type MergeRequest struct {
+	ContactIDs  []int                     `json:"contact_ids"`
+	SurvivorID  int                       `json:"survivor_id"`
+	DefaultRule string                    `json:"default_rule"`
+	Fields      map[string]FieldMergeRule `json:"fields"`
+}
+
+// MergedField representa el valor elegido para un campo durante una fusión
+type MergedField struct {
+	Field         string `json:"field"`
+	Rule          string `json:"rule"`
+	SourceID      int    `json:"source_id"`
+	Value         string `json:"value"`
+	PreviousValue string `json:"previous_value"`
+}
+
+// MergeRecord representa el registro de una fusión de contactos
+type MergeRecord struct {
+	ID         string        `json:"id"`
+	DatasetID  string        `json:"dataset_id"`
+	SurvivorID int           `json:"survivor_id"`
+	MergedIDs  []int         `json:"merged_ids"`
+	Fields     []MergedField `json:"fields"`
+	Merged     []Contact     `json:"merged"`
+	Result     Contact       `json:"result"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package entities
+
+// Clases de caracteres permitidas en los nombres
+const (
+	CharacterClassLetters     = "letters"
+	CharacterClassSpaces      = "spaces"
+	CharacterClassApostrophes = "apostrophes"
+	CharacterClassPeriods     = "periods"
+	CharacterClassHyphens     = "hyphens"
+	CharacterClassDigits      = "digits"
+)
+
+// ClientKeyPolicy define el formato aceptado para la clave cliente. Sin patrón la clave
+// debe ser numérica; una longitud en cero no se restringe.
+type ClientKeyPolicy struct {
+	Pattern   string `json:"pattern,omitempty" yaml:"pattern"`
+	MinLength int    `json:"min_length,omitempty" yaml:"min_length"`
+	MaxLength int    `json:"max_length,omitempty" yaml:"max_length"`
+}
+
+// NamePolicy define las clases de caracteres aceptadas en el nombre
+type NamePolicy struct {
+	CharacterClasses []string `json:"character_classes" yaml:"character_classes"`
+}
+
+// EmailPolicy define los dominios de email aceptados y rechazados. Sin dominios
+// permitidos se acepta cualquier dominio que no esté bloqueado.
+type EmailPolicy struct {
+	AllowedDomains []string `json:"allowed_domains" yaml:"allowed_domains"`
+	BlockedDomains []string `json:"blocked_domains" yaml:"blocked_domains"`
+}
+
+// PhonePolicy define los teléfonos aceptados: los países (códigos ISO, vacío acepta
+// cualquier país reconocido), la longitud de los números de México y sus ladas, por
+// lista o por estados del plan de numeración. Sin ladas ni estados se acepta cualquier
+// lada del plan; con ellas se rechazan los números extranjeros.
+type PhonePolicy struct {
+	Region    string   `json:"region,omitempty" yaml:"region"`
+	Countries []string `json:"countries,omitempty" yaml:"countries"`
+	AreaCodes []string `json:"area_codes" yaml:"area_codes"`
+	States    []string `json:"states,omitempty" yaml:"states"`
+	Length    int      `json:"length" yaml:"length"`
+}
+
+// ValidationProfile agrupa la configuración de validación de un cliente. Si Required
+// se omite todos los campos son obligatorios; si Rules se omite se ejecutan las reglas
+// incluidas en su orden por defecto. Severities cambia la severidad de las reglas
+// indicadas por nombre.
+type ValidationProfile struct {
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description" yaml:"description"`
+	Required    []string          `json:"required" yaml:"required"`
+	Rules       []string          `json:"rules,omitempty" yaml:"rules"`
+	Severities  map[string]string `json:"severities,omitempty" yaml:"severities"`
+	ClientKey   ClientKeyPolicy   `json:"client_key" yaml:"client_key"`
+	Names       NamePolicy        `json:"names" yaml:"names"`
+	Email       EmailPolicy       `json:"email" yaml:"email"`
+	Phone       PhonePolicy       `json:"phone" yaml:"phone"`
+}
+
+// ValidationProfileConfig representa el archivo de configuración de perfiles de validación
+type ValidationProfileConfig struct {
+	Default  string              `json:"default" yaml:"default"`
+	Profiles []ValidationProfile `json:"profiles" yaml:"profiles"`
+}
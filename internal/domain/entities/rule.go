@@ -0,0 +1,12 @@
+package entities
+
+// RuleInfo describe una regla de validación registrada
+type RuleInfo struct {
+	Name     string `json:"name"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Active   bool   `json:"active"`
+	Order    int    `json:"order,omitempty"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+// Tipo de valor que se agrupa en los valores más frecuentes de un campo
+const (
+	OffendingValueDomain   = "domain"
+	OffendingValueAreaCode = "area_code"
+	OffendingValueRaw      = "value"
+)
+
+// ValueCount representa cuántas veces aparece un valor
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// FieldSummary resume los errores de validación de un campo: cuántos hay por tipo y
+// por severidad y los valores que más se repiten entre ellos (el dominio de los
+// emails, la lada de los teléfonos y el valor completo de los demás campos)
+type FieldSummary struct {
+	Field      string         `json:"field"`
+	Errors     int            `json:"errors"`
+	ByType     map[string]int `json:"by_type"`
+	BySeverity map[string]int `json:"by_severity"`
+	ValueKind  string         `json:"value_kind"`
+	TopValues  []ValueCount   `json:"top_values"`
+}
+
+// ValidationSummary resume la validación de todos los contactos de un dataset
+type ValidationSummary struct {
+	DatasetID string          `json:"dataset_id"`
+	Profile   string          `json:"profile"`
+	Stats     ValidationStats `json:"stats"`
+	Fields    []FieldSummary  `json:"fields"`
+}
@@ -0,0 +1,57 @@
+package readers
+
+import (
+	"io"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// Workbook representa un archivo tabular abierto, con una o más hojas
+type Workbook interface {
+	Format() entities.FileFormat
+	SheetList() []string
+	Rows(sheet string) ([][]string, error)
+	Close() error
+}
+
+// RowIterator recorre las filas de una hoja una a la vez, desde el header
+type RowIterator interface {
+	Next() bool
+	Columns() ([]string, error)
+	Close() error
+}
+
+// RowStreamer lo implementan los libros que pueden leer una hoja fila por fila sin
+// cargarla completa en memoria
+type RowStreamer interface {
+	StreamRows(sheet string) (RowIterator, error)
+}
+
+// RowCounter lo implementan los libros que conocen el número de filas de una hoja sin
+// recorrerla, por ejemplo por la dimensión que guarda el archivo. El número no incluye el
+// header y puede ser una estimación; ok es false si no se puede obtener.
+type RowCounter interface {
+	RowCount(sheet string) (count int, ok bool)
+}
+
+// ReadProgress lo implementan los iteradores que saben qué parte de la hoja ya leyeron,
+// como los de archivos de texto por los bytes leídos
+type ReadProgress interface {
+	// ReadFraction retorna la fracción de la hoja ya leída, entre 0 y 1
+	ReadFraction() float64
+}
+
+// WorkbookReader define la interfaz para los lectores de un formato de archivo. El archivo
+// se lee con io.ReaderAt para no cargarlo completo en memoria; debe seguir abierto
+// mientras se use el libro.
+type WorkbookReader interface {
+	Name() string
+	// Detect reconoce el formato por el nombre del archivo y sus primeros bytes
+	Detect(fileName string, head []byte) bool
+	Open(fileName string, file io.ReaderAt, size int64) (Workbook, error)
+}
+
+// WorkbookOpener define la interfaz para abrir un archivo detectando su formato
+type WorkbookOpener interface {
+	Open(fileName string, file io.ReaderAt, size int64) (Workbook, error)
+}
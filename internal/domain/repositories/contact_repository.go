@@ -2,13 +2,38 @@ package repositories
 
 import "analizador-backend/internal/domain/entities"
 
+// StagingDatasetPrefix identifica los datasets provisionales en que una carga guarda sus
+// filas antes de aplicarlas; la restricción de clave única no se aplica a ellos porque las
+// filas repetidas de un archivo se reportan al aplicar la carga
+const StagingDatasetPrefix = "staging-"
+
 // ContactRepository define la interfaz para el repositorio de contactos
 type ContactRepository interface {
 	Save(contact *entities.Contact) error
-	FindAll() ([]*entities.Contact, error)
+	FindAll(datasetID string) ([]*entities.Contact, error)
 	FindByID(id int) (*entities.Contact, error)
 	Update(contact *entities.Contact) error
 	Delete(id int) error
-	Search(field, value string) ([]*entities.Contact, error)
+	Search(datasetID, field, value string) ([]*entities.Contact, error)
 	SaveBatch(contacts []*entities.Contact) error
+	UpdateBatch(contacts []*entities.Contact) error
+	Count(datasetID string) (int, error)
+	DeleteByDataset(datasetID string) error
+	DeleteBatch(ids []int) error
+	MoveToDataset(fromDatasetID, toDatasetID string) error
+	FindPage(datasetID string, afterID, limit int) ([]*entities.Contact, error)
+	FindByClientKeys(datasetID string, clientKeys []string) ([]*entities.Contact, error)
+	MergeByClientKey(fromDatasetID, toDatasetID string, absent AbsentContacts) (int, error)
+}
+
+// AbsentContacts indica qué hace MergeByClientKey con los contactos del dataset destino
+// cuya clave cliente no aparece en los contactos que se aplican
+type AbsentContacts struct {
+	// SeenDatasetID es un dataset cuyas claves también cuentan como presentes aunque sus
+	// contactos no se apliquen
+	SeenDatasetID string
+	// Status, si no está vacío, se asigna a los contactos ausentes que no lo tengan ya
+	Status string
+	// Remove elimina los contactos ausentes
+	Remove bool
 }
\ No newline at end of file
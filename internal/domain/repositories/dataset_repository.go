@@ -0,0 +1,12 @@
+package repositories
+
+import "analizador-backend/internal/domain/entities"
+
+// DatasetRepository define la interfaz para el repositorio de datasets
+type DatasetRepository interface {
+	Save(dataset *entities.Dataset) error
+	FindAll() ([]*entities.Dataset, error)
+	FindByID(id string) (*entities.Dataset, error)
+	Update(dataset *entities.Dataset) error
+	Delete(id string) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import "fmt"
+
+// DuplicateKeyError indica que otra entidad del mismo dataset ya usa la clave cliente
+type DuplicateKeyError struct {
+	DatasetID  string
+	ClientKey  string
+	ExistingID int
+}
+
+func (e *DuplicateKeyError) Error() string {
+	if e.ExistingID == 0 {
+		return fmt.Sprintf("la clave cliente %s está repetida en los contactos a guardar", e.ClientKey)
+	}
+	return fmt.Sprintf("la clave cliente %s ya existe en el contacto %d", e.ClientKey, e.ExistingID)
+}
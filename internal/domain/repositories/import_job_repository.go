@@ -0,0 +1,10 @@
+package repositories
+
+import "analizador-backend/internal/domain/entities"
+
+// ImportJobRepository define la interfaz para el repositorio de trabajos de importación
+type ImportJobRepository interface {
+	Save(job *entities.ImportJob) error
+	FindByID(id string) (*entities.ImportJob, error)
+	FindAll() ([]*entities.ImportJob, error)
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import "analizador-backend/internal/domain/entities"
+
+// MergeRecordRepository define la interfaz para el repositorio de registros de fusión
+type MergeRecordRepository interface {
+	Save(record *entities.MergeRecord) error
+	FindAll(datasetID string) ([]*entities.MergeRecord, error)
+	DeleteByDataset(datasetID string) error
+}
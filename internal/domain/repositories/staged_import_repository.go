@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+)
+
+// StagedImportRepository define la interfaz para el repositorio de cargas en vista previa
+type StagedImportRepository interface {
+	Save(staged *entities.StagedImport) error
+	FindByToken(token string) (*entities.StagedImport, error)
+	Delete(token string) error
+	DeleteExpired(now time.Time) ([]*entities.StagedImport, error)
+}
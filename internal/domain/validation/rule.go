@@ -0,0 +1,14 @@
+package validation
+
+// Rule define una regla de validación que se aplica al valor de un campo de contacto
+type Rule interface {
+	// Name identifica la regla en el registro (por ejemplo "email.domain")
+	Name() string
+	Field() string
+	// Code es el tipo de error que se reporta cuando el valor no cumple la regla
+	Code() string
+	Severity() string
+	Message() string
+	// Check indica si el valor cumple la regla
+	Check(value string) bool
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	"analizador-backend/internal/domain/entities"
+)
+
+// LoadValidationProfiles lee los perfiles de validación de un archivo YAML o JSON
+func LoadValidationProfiles(path string) (entities.ValidationProfileConfig, error) {
+	var config entities.ValidationProfileConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("no se pudo leer la configuración de perfiles: %w", err)
+	}
+
+	// JSON es un subconjunto de YAML, así que el mismo decodificador sirve para ambos
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("la configuración de perfiles no es válida: %w", err)
+	}
+	if len(config.Profiles) == 0 {
+		return config, fmt.Errorf("la configuración de perfiles no define ningún perfil")
+	}
+	return config, nil
+}
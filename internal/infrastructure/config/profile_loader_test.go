@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"analizador-backend/internal/application/services"
+)
+
+func TestLoadValidationProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		profiles []string
+		err      string
+	}{
+		{
+			name: "YAML",
+			content: `default: ventas
+profiles:
+  - name: ventas
+    required: [client_key, name]
+    phone:
+      area_codes: ["961"]
+  - name: soporte
+`,
+			profiles: []string{"ventas", "soporte"},
+		},
+		{
+			name:     "JSON",
+			content:  `{"default": "ventas", "profiles": [{"name": "ventas", "email": {"blocked_domains": ["test.com"]}}]}`,
+			profiles: []string{"ventas"},
+		},
+		{
+			name:    "sin perfiles",
+			content: "default: ventas\n",
+			err:     "no define ningún perfil",
+		},
+		{
+			name:    "formato inválido",
+			content: "profiles: [nombre: {",
+			err:     "no es válida",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "perfiles.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadValidationProfiles(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("LoadValidationProfiles() error = %v, se esperaba %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadValidationProfiles() error = %v", err)
+			}
+			var names []string
+			for _, profile := range config.Profiles {
+				names = append(names, profile.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.profiles, ",") {
+				t.Errorf("perfiles = %v, se esperaba %v", names, tt.profiles)
+			}
+		})
+	}
+
+	if _, err := LoadValidationProfiles(filepath.Join(t.TempDir(), "no-existe.yaml")); err == nil {
+		t.Errorf("LoadValidationProfiles() de un archivo inexistente no retornó error")
+	}
+}
+
+func TestBundledValidationProfiles(t *testing.T) {
+	// El archivo de ejemplo del repositorio debe cargar en el validador
+	config, err := LoadValidationProfiles(filepath.Join("..", "..", "..", "config", "validation_profiles.yaml"))
+	if err != nil {
+		t.Fatalf("LoadValidationProfiles() error = %v", err)
+	}
+	validator := services.NewValidatorService()
+	if err := validator.LoadProfiles(config); err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+	for _, profile := range config.Profiles {
+		if _, err := validator.ForProfile(profile.Name); err != nil {
+			t.Errorf("ForProfile(%s) error = %v", profile.Name, err)
+		}
+	}
+}
@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -11,114 +16,366 @@ import (
 	"github.com/xuri/excelize/v2"
 	"analizador-backend/internal/application/services"
 	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+	"analizador-backend/internal/domain/repositories"
 )
 
 type ContactHandler struct {
-	contactService *services.ContactService
+	contactService   *services.ContactService
+	datasetService   *services.DatasetService
+	importService    *services.ImportService
+	importJobService *services.ImportJobService
+	workbookOpener   readers.WorkbookOpener
 }
 
 // NewContactHandler crea una nueva instancia del handler de contactos
-func NewContactHandler(contactService *services.ContactService) *ContactHandler {
+func NewContactHandler(contactService *services.ContactService, datasetService *services.DatasetService, importService *services.ImportService, importJobService *services.ImportJobService, workbookOpener readers.WorkbookOpener) *ContactHandler {
 	return &ContactHandler{
-		contactService: contactService,
+		contactService:   contactService,
+		datasetService:   datasetService,
+		importService:    importService,
+		importJobService: importJobService,
+		workbookOpener:   workbookOpener,
 	}
 }
 
-// UploadExcel maneja la carga de archivos Excel
-func (h *ContactHandler) UploadExcel(c *gin.Context) {
-	file, _, err := c.Request.FormFile("file")
+// saveErrorStatus obtiene el código HTTP y el mensaje para un error al guardar contactos
+func saveErrorStatus(err error, message string) (int, string) {
+	var duplicateErr *repositories.DuplicateKeyError
+	if errors.As(err, &duplicateErr) {
+		return http.StatusConflict, duplicateErr.Error()
+	}
+	return http.StatusInternalServerError, message
+}
+
+// datasetID obtiene el dataset de la ruta y verifica que exista
+func (h *ContactHandler) datasetID(c *gin.Context) (string, bool) {
+	return requireDataset(c, h.datasetService)
+}
+
+// requireDataset obtiene el dataset de la ruta y responde 404 si no existe. Las rutas
+// anteriores a los datasets no incluyen uno y usan el cargado más recientemente.
+func requireDataset(c *gin.Context, datasetService *services.DatasetService) (string, bool) {
+	id := c.Param("dataset_id")
+	if id == "" {
+		dataset, err := datasetService.LatestDataset()
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No hay datasets cargados"})
+			return "", false
+		}
+		return dataset.ID, true
+	}
+
+	dataset, err := datasetService.GetDataset(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dataset no encontrado"})
+		return "", false
+	}
+	return dataset.ID, true
+}
+
+// validationProfile obtiene el perfil de validación del parámetro 'profile' o, si no se
+// indica, el del dataset, y responde 400 si el perfil no existe
+func validationProfile(c *gin.Context, datasetService *services.DatasetService, datasetID string) (string, bool) {
+	profile, err := datasetService.ValidationProfile(datasetID, c.Query("profile"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return profile, true
+}
+
+// targetDatasetID obtiene el dataset destino de una carga: el de la ruta o, si la
+// ruta no incluye uno, vacío para crear un dataset nuevo
+func (h *ContactHandler) targetDatasetID(c *gin.Context) (string, bool) {
+	if c.Param("dataset_id") == "" {
+		return "", true
+	}
+	return h.datasetID(c)
+}
+
+// openWorkbook copia el archivo del formulario a un archivo temporal y lo abre detectando
+// su formato, de modo que los lectores lo leen del disco conforme lo necesitan en lugar
+// de cargarlo completo en memoria. Cerrar el libro elimina el archivo temporal; la copia
+// permite que una carga en segundo plano siga leyéndolo después de responder.
+func (h *ContactHandler) openWorkbook(c *gin.Context) (readers.Workbook, string, bool) {
+	file, fileHeader, err := c.Request.FormFile("file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el archivo"})
-		return
+		return nil, "", false
 	}
 	defer file.Close()
 
-	// Leer archivo Excel
-	f, err := excelize.OpenReader(file)
+	temp, err := os.CreateTemp("", "carga-*")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo abrir el archivo Excel"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo guardar el archivo"})
+		return nil, "", false
+	}
+	size, err := io.Copy(temp, file)
+	if err != nil {
+		removeTempFile(temp)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el archivo"})
+		return nil, "", false
+	}
+
+	workbook, err := h.workbookOpener.Open(fileHeader.Filename, temp, size)
+	if err != nil {
+		removeTempFile(temp)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo abrir el archivo: " + err.Error()})
+		return nil, "", false
+	}
+
+	return &uploadedWorkbook{Workbook: workbook, file: temp}, fileHeader.Filename, true
+}
+
+// uploadedWorkbook es un libro abierto sobre la copia temporal de un archivo subido
+type uploadedWorkbook struct {
+	readers.Workbook
+	file *os.File
+}
+
+// StreamRows recorre la hoja fila por fila si el lector lo permite
+func (w *uploadedWorkbook) StreamRows(sheet string) (readers.RowIterator, error) {
+	return services.OpenRows(w.Workbook, sheet)
+}
+
+// RowCount obtiene el número de filas de la hoja si el lector lo conoce sin recorrerla
+func (w *uploadedWorkbook) RowCount(sheet string) (int, bool) {
+	if counter, ok := w.Workbook.(readers.RowCounter); ok {
+		return counter.RowCount(sheet)
+	}
+	return 0, false
+}
+
+// Close cierra el libro y elimina el archivo temporal
+func (w *uploadedWorkbook) Close() error {
+	err := w.Workbook.Close()
+	removeTempFile(w.file)
+	return err
+}
+
+// removeTempFile cierra y elimina un archivo temporal
+func removeTempFile(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// importOptions lee la hoja a importar y el mapeo explícito de columnas del formulario
+func (h *ContactHandler) importOptions(c *gin.Context) (entities.ImportOptions, bool) {
+	// Hoja: por nombre, índice o todas ("*"); por defecto la primera
+	options := entities.ImportOptions{
+		Sheet:   c.DefaultPostForm("sheet", c.Query("sheet")),
+		Mode:    c.DefaultPostForm("mode", c.Query("mode")),
+		Missing: c.DefaultPostForm("missing", c.Query("missing")),
+		Profile: c.DefaultPostForm("profile", c.Query("profile")),
+	}
+
+	// Mapeo explícito opcional de columnas: {"campo": "encabezado o letra de columna"}
+	if mappingStr := c.PostForm("mapping"); mappingStr != "" {
+		if err := json.Unmarshal([]byte(mappingStr), &options.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "El mapeo de columnas no es un JSON válido"})
+			return options, false
+		}
+	}
+
+	return options, true
+}
+
+// UploadExcel maneja la carga de hojas de cálculo (XLSX, XLS, ODS) y de texto delimitado (CSV, TSV).
+// Sin dataset en la ruta se crea un dataset nuevo con el nombre del campo "name" o del archivo.
+// Con async=true el archivo se procesa en segundo plano y se responde con el trabajo creado.
+func (h *ContactHandler) UploadExcel(c *gin.Context) {
+	datasetID, ok := h.targetDatasetID(c)
+	if !ok {
+		return
+	}
+
+	options, ok := h.importOptions(c)
+	if !ok {
+		return
+	}
+
+	f, fileName, ok := h.openWorkbook(c)
+	if !ok {
+		return
+	}
+
+	if async, _ := strconv.ParseBool(c.DefaultPostForm("async", c.Query("async"))); async {
+		h.startImportJob(c, f, fileName, datasetID, options)
 		return
 	}
 	defer f.Close()
 
-	// Obtener la primera hoja
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo no contiene hojas"})
+	// Guardar contactos; si el cliente cierra la conexión la carga se detiene y se descarta
+	commit, err := h.importService.Upload(c.Request.Context(), f, fileName, datasetID, c.PostForm("name"), options, nil)
+	var readErr *services.UploadReadError
+	if errors.As(err, &readErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": readErr.Error()})
+		return
+	}
+	if err != nil {
+		status, message := saveErrorStatus(err, "No se pudieron guardar los contactos")
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
 
-	rows, err := f.GetRows(sheets[0])
+	result := commit.Result
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Archivo cargado exitosamente",
+		"dataset":        commit.Dataset,
+		"outcome":        commit.Outcome,
+		"upload_id":      result.UploadID,
+		"format":         result.Format,
+		"count":          result.Report.Imported,
+		"report":         result.Report,
+		"sheets":         result.Sheets,
+		"skipped_sheets": result.SkippedSheets,
+		"validation":     commit.Validation,
+	})
+}
+
+// startImportJob crea el trabajo que procesa la carga en segundo plano y responde con su estado
+func (h *ContactHandler) startImportJob(c *gin.Context, f readers.Workbook, fileName, datasetID string, options entities.ImportOptions) {
+	job, err := h.importJobService.Start(f, fileName, datasetID, c.PostForm("name"), options)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudieron leer las filas"})
+		f.Close()
+		var readErr *services.UploadReadError
+		if errors.As(err, &readErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": readErr.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrTooManyImportJobs) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo crear el trabajo de importación"})
 		return
 	}
 
-	if len(rows) < 2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "El archivo debe contener al menos un registro además del header"})
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Archivo recibido; la carga se procesa en segundo plano",
+		"job":     job,
+	})
+}
+
+// PreviewUpload lee y valida un archivo sin guardarlo; la carga queda en espera de confirmación
+func (h *ContactHandler) PreviewUpload(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "20")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	datasetID, ok := h.targetDatasetID(c)
+	if !ok {
 		return
 	}
 
-	// Procesar filas (saltar header)
-	var contacts []*entities.Contact
-	for i, row := range rows[1:] {
-		if len(row) < 4 {
-			continue
-		}
+	options, ok := h.importOptions(c)
+	if !ok {
+		return
+	}
 
-		i=i
-		// Limpiar y procesar cada campo
-		clientKey := strings.TrimSpace(fmt.Sprintf("%v", row[0]))
-		name := strings.TrimSpace(fmt.Sprintf("%v", row[1]))
-		email := strings.TrimSpace(fmt.Sprintf("%v", row[2]))
-		phone := strings.TrimSpace(fmt.Sprintf("%v", row[3]))
-
-		// Remover caracteres no deseados del teléfono (espacios, guiones, etc.)
-		phoneClean := strings.ReplaceAll(phone, " ", "")
-		phoneClean = strings.ReplaceAll(phoneClean, "-", "")
-		phoneClean = strings.ReplaceAll(phoneClean, "(", "")
-		phoneClean = strings.ReplaceAll(phoneClean, ")", "")
-
-		contact := &entities.Contact{
-			ClientKey: clientKey,
-			Name:      name,
-			Email:     email,
-			Phone:     phoneClean,
-		}
+	f, fileName, ok := h.openWorkbook(c)
+	if !ok {
+		return
+	}
+	defer f.Close()
 
-		contacts = append(contacts, contact)
+	preview, err := h.importService.Preview(c.Request.Context(), f, fileName, datasetID, c.PostForm("name"), options, limit)
+	var readErr *services.UploadReadError
+	if errors.As(err, &readErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": readErr.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo generar la vista previa"})
+		return
 	}
 
-	// Guardar contactos
-	err = h.contactService.SaveContactsBatch(contacts)
+	c.JSON(http.StatusOK, preview)
+}
+
+// ConfirmImport guarda los contactos de una carga en vista previa
+func (h *ContactHandler) ConfirmImport(c *gin.Context) {
+	commit, err := h.importService.ConfirmStaged(c.Request.Context(), c.Param("token"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron guardar los contactos"})
+		var duplicateErr *repositories.DuplicateKeyError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": duplicateErr.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Archivo cargado exitosamente",
-		"count":   len(contacts),
+		"message":    "Carga confirmada exitosamente",
+		"dataset":    commit.Dataset,
+		"outcome":    commit.Outcome,
+		"upload_id":  commit.Result.UploadID,
+		"count":      commit.Result.Report.Imported,
+		"report":     commit.Result.Report,
+		"validation": commit.Validation,
 	})
 }
 
-// GetContacts obtiene contactos con paginación
+// DiscardImport descarta una carga en vista previa
+func (h *ContactHandler) DiscardImport(c *gin.Context) {
+	if err := h.importService.DiscardStaged(c.Param("token")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Carga descartada exitosamente"})
+}
+
+// ListSheets lista las hojas de un libro con su número de registros y encabezados detectados
+func (h *ContactHandler) ListSheets(c *gin.Context) {
+	f, _, ok := h.openWorkbook(c)
+	if !ok {
+		return
+	}
+	defer f.Close()
+
+	sheets := []entities.SheetInfo{}
+	for index, sheet := range f.SheetList() {
+		info, err := h.importService.InspectSheet(f, index, sheet)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudieron leer las filas"})
+			return
+		}
+		sheets = append(sheets, info)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"format": f.Format(),
+		"data":   sheets,
+	})
+}
+
+// GetContacts obtiene contactos de un dataset con paginación
 func (h *ContactHandler) GetContacts(c *gin.Context) {
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
 	// Parámetros de paginación
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "50")
-	
+
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
 		page = 1
 	}
-	
+
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 || limit > 100 {
 		limit = 50
 	}
 
-	allContacts, err := h.contactService.GetAllContacts()
+	allContacts, err := h.contactService.GetAllContacts(datasetID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener los contactos"})
 		return
@@ -153,8 +410,13 @@ func (h *ContactHandler) GetContacts(c *gin.Context) {
 	})
 }
 
-// SearchContacts busca contactos por diferentes campos
+// SearchContacts busca contactos de un dataset por diferentes campos
 func (h *ContactHandler) SearchContacts(c *gin.Context) {
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
 	field := c.Query("field")
 	value := c.Query("value")
 
@@ -163,7 +425,7 @@ func (h *ContactHandler) SearchContacts(c *gin.Context) {
 		return
 	}
 
-	contacts, err := h.contactService.SearchContacts(field, value)
+	contacts, err := h.contactService.SearchContacts(datasetID, field, value)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error en la búsqueda"})
 		return
@@ -172,8 +434,13 @@ func (h *ContactHandler) SearchContacts(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": contacts})
 }
 
-// UpdateContact actualiza un contacto específico
+// UpdateContact actualiza un contacto específico del dataset
 func (h *ContactHandler) UpdateContact(c *gin.Context) {
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -183,61 +450,100 @@ func (h *ContactHandler) UpdateContact(c *gin.Context) {
 
 	var contact entities.Contact
 	if err := c.ShouldBindJSON(&contact); err != nil {
-		fmt.Printf("Error binding JSON: %v\n", err)
+		log.Printf("Error binding JSON: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
 		return
 	}
 
-	// Debug: mostrar los datos recibidos
-	fmt.Printf("DEBUG: Actualizando contacto ID %d\n", id)
-	fmt.Printf("DEBUG: Datos recibidos: %+v\n", contact)
-
 	contact.ID = id
-	
+
 	// Limpiar y validar datos antes de actualizar
 	contact.ClientKey = strings.TrimSpace(contact.ClientKey)
 	contact.Name = strings.TrimSpace(contact.Name)
 	contact.Email = strings.TrimSpace(contact.Email)
 	contact.Phone = strings.TrimSpace(contact.Phone)
-	
-	fmt.Printf("DEBUG: Datos después de limpiar: %+v\n", contact)
-	
-	err = h.contactService.UpdateContact(&contact)
+
+	err = h.contactService.UpdateContact(datasetID, &contact)
 	if err != nil {
-		fmt.Printf("Error updating contact: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar el contacto"})
+		log.Printf("Error updating contact: %v", err)
+		status, message := saveErrorStatus(err, "No se pudo actualizar el contacto")
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
 
-	fmt.Printf("DEBUG: Contacto actualizado exitosamente\n")
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Contacto actualizado exitosamente",
 		"contact": contact,
 	})
 }
 
-// ValidateContacts valida todos los contactos y retorna errores con paginación
+// DeleteContact elimina un contacto específico del dataset
+func (h *ContactHandler) DeleteContact(c *gin.Context) {
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := h.contactService.DeleteContact(datasetID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contacto no encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contacto eliminado exitosamente"})
+}
+
+// ValidateContacts valida todos los contactos del dataset y retorna errores con paginación.
+// Los parámetros validity, field, type y q filtran los resultados; la paginación y las
+// estadísticas se calculan sobre los resultados filtrados.
 func (h *ContactHandler) ValidateContacts(c *gin.Context) {
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
 	// Parámetros de paginación
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "50")
-	
+
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
 		page = 1
 	}
-	
+
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 || limit > 100 {
 		limit = 50
 	}
 
-	// Obtener todas las validaciones
-	allResults, err := h.contactService.ValidateAllContacts()
+	filter := entities.ValidationFilter{
+		Validity: c.Query("validity"),
+		Field:    c.Query("field"),
+		Type:     c.Query("type"),
+		Text:     c.Query("q"),
+	}
+	if err := services.CheckValidationFilter(filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, ok := validationProfile(c, h.datasetService, datasetID)
+	if !ok {
+		return
+	}
+
+	// Obtener todas las validaciones y conservar las que cumplen el filtro
+	allResults, err := h.contactService.ValidateAllContacts(datasetID, profile)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo validar los contactos"})
 		return
 	}
+	allResults = services.FilterResults(allResults, filter)
 
 	total := len(allResults)
 	start := (page - 1) * limit
@@ -258,15 +564,7 @@ func (h *ContactHandler) ValidateContacts(c *gin.Context) {
 	totalPages := (total + limit - 1) / limit
 
 	// Calcular estadísticas de todos los resultados
-	validCount := 0
-	invalidCount := 0
-	for _, result := range allResults {
-		if result.IsValid {
-			validCount++
-		} else {
-			invalidCount++
-		}
-	}
+	stats := services.SummarizeResults(allResults)
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":        paginatedResults,
@@ -276,25 +574,57 @@ func (h *ContactHandler) ValidateContacts(c *gin.Context) {
 		"total_pages": totalPages,
 		"has_next":    page < totalPages,
 		"has_prev":    page > 1,
+		"filter":      filter,
 		"stats": gin.H{
-			"total":   total,
-			"valid":   validCount,
-			"invalid": invalidCount,
+			"total":               stats.Total,
+			"valid":               stats.Valid,
+			"valid_with_warnings": stats.ValidWithWarnings,
+			"invalid":             stats.Invalid,
+			"by_severity":         stats.ErrorsBySeverity,
 		},
 	})
 }
 
-// DownloadExcel genera y descarga un archivo Excel con todos los contactos actuales
+// GetValidationSummary resume la validación de todos los contactos del dataset por campo,
+// tipo de error y severidad, con los valores con errores más frecuentes de cada campo
+func (h *ContactHandler) GetValidationSummary(c *gin.Context) {
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil || top < 1 || top > 100 {
+		top = 10
+	}
+
+	profile, ok := validationProfile(c, h.datasetService, datasetID)
+	if !ok {
+		return
+	}
+
+	summary, err := h.contactService.ValidationSummary(datasetID, profile, top)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo validar los contactos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": summary})
+}
+
+// DownloadExcel genera y descarga un archivo Excel con todos los contactos actuales del dataset
 func (h *ContactHandler) DownloadExcel(c *gin.Context) {
-	contacts, err := h.contactService.GetAllContacts()
+	datasetID, ok := h.datasetID(c)
+	if !ok {
+		return
+	}
+
+	contacts, err := h.contactService.GetAllContacts(datasetID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener los contactos"})
 		return
 	}
 
-	// Debug: verificar si hay contactos
-	fmt.Printf("DEBUG: Número de contactos encontrados: %d\n", len(contacts))
-
 	if len(contacts) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No hay contactos para descargar"})
 		return
@@ -304,32 +634,32 @@ func (h *ContactHandler) DownloadExcel(c *gin.Context) {
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
-			fmt.Println(err)
+			log.Println(err)
 		}
 	}()
-	
+
 	sheetName := "Sheet1" // Cambiar a Sheet1 que es el default
-	
+
 	// Headers exactos como en tu estructura
-	headers := []string{"Clave cliente", "   Nombre Contacto ", "Correo ", "Teléfono Contacto  "}
+	headers := []string{"Clave cliente", "   Nombre Contacto ", "Correo ", "Teléfono Contacto  ", "Teléfono E.164"}
 	for i, header := range headers {
 		cell := fmt.Sprintf("%c1", 'A'+i)
 		err := f.SetCellValue(sheetName, cell, header)
 		if err != nil {
-			fmt.Printf("Error setting header %s: %v\n", header, err)
+			log.Printf("Error setting header %s: %v", header, err)
 		}
 	}
 
 	// Datos de contactos
 	for i, contact := range contacts {
 		row := i + 2
-		fmt.Printf("DEBUG: Procesando contacto %d: %+v\n", i+1, contact)
-		
+
 		// Asegurar que todos los valores se escriban correctamente
 		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), contact.ClientKey)
 		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), contact.Name)
 		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), contact.Email)
 		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), contact.Phone)
+		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), contact.PhoneE164)
 	}
 
 	// Ajustar anchos de columna
@@ -337,18 +667,16 @@ func (h *ContactHandler) DownloadExcel(c *gin.Context) {
 	f.SetColWidth(sheetName, "B", "B", 35)
 	f.SetColWidth(sheetName, "C", "C", 40)
 	f.SetColWidth(sheetName, "D", "D", 18)
+	f.SetColWidth(sheetName, "E", "E", 18)
 
 	// Crear buffer temporal para escribir el archivo
 	buf := new(bytes.Buffer)
 	if err := f.Write(buf); err != nil {
-		fmt.Printf("Error writing to buffer: %v\n", err)
+		log.Printf("Error writing to buffer: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo generar el archivo Excel"})
 		return
 	}
 
-	// Verificar que el buffer tiene contenido
-	fmt.Printf("DEBUG: Tamaño del archivo Excel: %d bytes\n", buf.Len())
-
 	// Configurar headers para descarga
 	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
 	c.Header("Content-Disposition", "attachment; filename=\"contactos_corregidos.xlsx\"")
@@ -356,7 +684,7 @@ func (h *ContactHandler) DownloadExcel(c *gin.Context) {
 
 	// Escribir directamente el buffer al response
 	if _, err := c.Writer.Write(buf.Bytes()); err != nil {
-		fmt.Printf("Error writing response: %v\n", err)
+		log.Printf("Error writing response: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo enviar el archivo"})
 		return
 	}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"analizador-backend/internal/application/services"
+)
+
+type DatasetHandler struct {
+	datasetService *services.DatasetService
+}
+
+// NewDatasetHandler crea una nueva instancia del handler de datasets
+func NewDatasetHandler(datasetService *services.DatasetService) *DatasetHandler {
+	return &DatasetHandler{
+		datasetService: datasetService,
+	}
+}
+
+// GetDatasets lista todos los datasets
+func (h *DatasetHandler) GetDatasets(c *gin.Context) {
+	datasets, err := h.datasetService.GetAllDatasets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener los datasets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": datasets})
+}
+
+// GetDataset obtiene un dataset por ID
+func (h *DatasetHandler) GetDataset(c *gin.Context) {
+	dataset, err := h.datasetService.GetDataset(c.Param("dataset_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dataset no encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dataset})
+}
+
+// DeleteDataset elimina un dataset y todos sus contactos
+func (h *DatasetHandler) DeleteDataset(c *gin.Context) {
+	if err := h.datasetService.DeleteDataset(c.Param("dataset_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dataset no encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dataset eliminado exitosamente"})
+}
+
+// UpdateDatasetProfile cambia el perfil de validación de un dataset
+func (h *DatasetHandler) UpdateDatasetProfile(c *gin.Context) {
+	datasetID, ok := requireDataset(c, h.datasetService)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Profile string `json:"profile"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	dataset, err := h.datasetService.SetProfile(datasetID, request.Profile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Perfil de validación actualizado exitosamente",
+		"data":    dataset,
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"analizador-backend/internal/application/services"
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type DuplicateHandler struct {
+	duplicateService *services.DuplicateService
+	mergeService     *services.MergeService
+	datasetService   *services.DatasetService
+}
+
+// NewDuplicateHandler crea una nueva instancia del handler de duplicados
+func NewDuplicateHandler(duplicateService *services.DuplicateService, mergeService *services.MergeService, datasetService *services.DatasetService) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicateService: duplicateService,
+		mergeService:     mergeService,
+		datasetService:   datasetService,
+	}
+}
+
+// GetDuplicates lista los grupos de contactos probablemente duplicados del dataset
+func (h *DuplicateHandler) GetDuplicates(c *gin.Context) {
+	datasetID, ok := requireDataset(c, h.datasetService)
+	if !ok {
+		return
+	}
+
+	minScoreStr := c.DefaultQuery("min_score", "0.5")
+	minScore, err := strconv.ParseFloat(minScoreStr, 64)
+	if err != nil || minScore < 0 || minScore > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El parámetro 'min_score' debe estar entre 0 y 1"})
+		return
+	}
+
+	clusters, err := h.duplicateService.FindDuplicates(datasetID, minScore)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron buscar los duplicados"})
+		return
+	}
+	if clusters == nil {
+		clusters = []*entities.DuplicateCluster{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  clusters,
+		"total": len(clusters),
+	})
+}
+
+// MergeContacts fusiona un grupo de contactos duplicados en un sobreviviente
+func (h *DuplicateHandler) MergeContacts(c *gin.Context) {
+	datasetID, ok := requireDataset(c, h.datasetService)
+	if !ok {
+		return
+	}
+
+	var request entities.MergeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	profile, ok := validationProfile(c, h.datasetService, datasetID)
+	if !ok {
+		return
+	}
+
+	record, err := h.mergeService.MergeContacts(datasetID, profile, request)
+	if err != nil {
+		var duplicateErr *repositories.DuplicateKeyError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contactos fusionados exitosamente",
+		"merge":   record,
+	})
+}
+
+// GetMerges lista el historial de fusiones del dataset
+func (h *DuplicateHandler) GetMerges(c *gin.Context) {
+	datasetID, ok := requireDataset(c, h.datasetService)
+	if !ok {
+		return
+	}
+
+	records, err := h.mergeService.GetMergeRecords(datasetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo obtener el historial de fusiones"})
+		return
+	}
+	if records == nil {
+		records = []*entities.MergeRecord{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  records,
+		"total": len(records),
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"analizador-backend/internal/application/services"
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type FixHandler struct {
+	fixService     *services.FixService
+	datasetService *services.DatasetService
+}
+
+// NewFixHandler crea una nueva instancia del handler de correcciones masivas
+func NewFixHandler(fixService *services.FixService, datasetService *services.DatasetService) *FixHandler {
+	return &FixHandler{
+		fixService:     fixService,
+		datasetService: datasetService,
+	}
+}
+
+// GetFixes lista las correcciones propuestas para los contactos inválidos del dataset
+func (h *FixHandler) GetFixes(c *gin.Context) {
+	datasetID, ok := requireDataset(c, h.datasetService)
+	if !ok {
+		return
+	}
+
+	profile, ok := validationProfile(c, h.datasetService, datasetID)
+	if !ok {
+		return
+	}
+
+	fixes, err := h.fixService.ProposeFixes(datasetID, profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron calcular las correcciones"})
+		return
+	}
+	if fixes == nil {
+		fixes = []entities.ContactFix{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  fixes,
+		"total": len(fixes),
+	})
+}
+
+// ApplyFixes aplica todas las correcciones propuestas del dataset o solo las aceptadas
+func (h *FixHandler) ApplyFixes(c *gin.Context) {
+	datasetID, ok := requireDataset(c, h.datasetService)
+	if !ok {
+		return
+	}
+
+	var request entities.ApplyFixesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	profile, ok := validationProfile(c, h.datasetService, datasetID)
+	if !ok {
+		return
+	}
+
+	result, err := h.fixService.ApplyFixes(datasetID, profile, request)
+	if err != nil {
+		var duplicateErr *repositories.DuplicateKeyError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var conflictErr *services.FixConflictError
+		if errors.As(err, &conflictErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "current": conflictErr.Current})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Correcciones aplicadas exitosamente",
+		"result":  result,
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"analizador-backend/internal/application/services"
+)
+
+type ImportJobHandler struct {
+	importJobService *services.ImportJobService
+}
+
+// NewImportJobHandler crea una nueva instancia del handler de trabajos de importación
+func NewImportJobHandler(importJobService *services.ImportJobService) *ImportJobHandler {
+	return &ImportJobHandler{
+		importJobService: importJobService,
+	}
+}
+
+// GetJobs lista los trabajos de importación, del más reciente al más antiguo, sin su reporte
+func (h *ImportJobHandler) GetJobs(c *gin.Context) {
+	jobs, err := h.importJobService.GetJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener los trabajos de importación"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": jobs})
+}
+
+// GetJob obtiene el estado, el avance y, al terminar, el reporte de un trabajo de importación
+func (h *ImportJobHandler) GetJob(c *gin.Context) {
+	job, err := h.importJobService.GetJob(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// CancelJob solicita la cancelación de un trabajo de importación en curso
+func (h *ImportJobHandler) CancelJob(c *gin.Context) {
+	if _, err := h.importJobService.GetJob(c.Param("job_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.importJobService.CancelJob(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Cancelación solicitada",
+		"data":    job,
+	})
+}
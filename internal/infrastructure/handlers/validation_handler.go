@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"analizador-backend/internal/application/services"
+)
+
+type ValidationHandler struct {
+	validatorService *services.ValidatorService
+}
+
+// NewValidationHandler crea una nueva instancia del handler de reglas de validación
+func NewValidationHandler(validatorService *services.ValidatorService) *ValidationHandler {
+	return &ValidationHandler{
+		validatorService: validatorService,
+	}
+}
+
+// GetRules lista las reglas de validación registradas en un perfil y las que están activas
+func (h *ValidationHandler) GetRules(c *gin.Context) {
+	validator, err := h.validatorService.ForProfile(c.Query("profile"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules := validator.DescribeRules()
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  rules,
+		"total": len(rules),
+	})
+}
+
+// GetProfiles lista los perfiles de validación disponibles
+func (h *ValidationHandler) GetProfiles(c *gin.Context) {
+	profiles, defaultProfile := h.validatorService.Profiles()
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    profiles,
+		"default": defaultProfile,
+		"total":   len(profiles),
+	})
+}
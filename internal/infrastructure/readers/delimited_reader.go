@@ -0,0 +1,414 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+)
+
+const (
+	// sniffLines es el número de líneas que se analizan para detectar el delimitador
+	sniffLines = 20
+	// sniffSampleSize es el máximo de bytes del inicio del texto en que se buscan esas líneas
+	sniffSampleSize = 64 * 1024
+	// scanChunkSize es el tamaño de los bloques en que se recorre el archivo para detectar su codificación
+	scanChunkSize = 64 * 1024
+)
+
+// candidateDelimiters son los delimitadores que se intentan detectar, en orden de preferencia
+var candidateDelimiters = []rune{',', ';', '\t', '|'}
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+type DelimitedReader struct{}
+
+// NewDelimitedReader crea un lector de archivos de texto delimitado (CSV, TSV, punto y coma)
+func NewDelimitedReader() readers.WorkbookReader {
+	return &DelimitedReader{}
+}
+
+// Name retorna el nombre del formato
+func (r *DelimitedReader) Name() string {
+	return "csv"
+}
+
+// Detect reconoce archivos de texto por su extensión o, en su defecto, por su contenido
+func (r *DelimitedReader) Detect(fileName string, head []byte) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv", ".tsv", ".tab", ".txt":
+		return true
+	}
+
+	if bytes.HasPrefix(head, utf16LEBOM) || bytes.HasPrefix(head, utf16BEBOM) {
+		return true
+	}
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	return len(head) > 0 && bytes.IndexByte(head, 0) == -1
+}
+
+// Open detecta la codificación y el delimitador del archivo. Las filas no se cargan al
+// abrirlo: se leen del archivo cada vez que se recorre la hoja.
+func (r *DelimitedReader) Open(fileName string, file io.ReaderAt, size int64) (readers.Workbook, error) {
+	source, err := detectEncoding(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	sample, err := readSample(source.text())
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter := sniffDelimiter(sample)
+	if strings.EqualFold(filepath.Ext(fileName), ".tsv") && strings.ContainsRune(sample, '\t') {
+		delimiter = '\t'
+	}
+
+	name := "csv"
+	if delimiter == '\t' {
+		name = "tsv"
+	}
+
+	sheet := strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	if sheet == "" || sheet == "." {
+		sheet = "Hoja1"
+	}
+
+	return &delimitedWorkbook{
+		format: entities.FileFormat{
+			Name:      name,
+			Delimiter: string(delimiter),
+			Encoding:  source.encoding,
+		},
+		sheet:     sheet,
+		source:    source,
+		delimiter: delimiter,
+	}, nil
+}
+
+// textSource lee el archivo convertido a UTF-8 desde el inicio
+type textSource struct {
+	file     io.ReaderAt
+	size     int64
+	encoding string
+	// skip es el número de bytes del BOM que se omiten
+	skip    int64
+	decoder func() *encoding.Decoder
+}
+
+// text abre un lector nuevo del texto en UTF-8
+func (s *textSource) text() io.Reader {
+	return s.decode(io.NewSectionReader(s.file, s.skip, s.size-s.skip))
+}
+
+// decode convierte a UTF-8 el contenido leído del archivo
+func (s *textSource) decode(raw io.Reader) io.Reader {
+	if s.decoder == nil {
+		return raw
+	}
+	return transform.NewReader(raw, s.decoder())
+}
+
+// detectEncoding reconoce la codificación por el BOM o, sin BOM, recorriendo el archivo
+// en bloques: UTF-8 si todo el contenido es válido y si no Latin-1/Windows-1252
+func detectEncoding(file io.ReaderAt, size int64) (*textSource, error) {
+	source := &textSource{file: file, size: size}
+
+	bom := make([]byte, min(size, int64(len(utf8BOM))))
+	if _, err := file.ReadAt(bom, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(bom, utf8BOM):
+		source.encoding, source.skip = "utf-8-bom", int64(len(utf8BOM))
+		return source, nil
+	case bytes.HasPrefix(bom, utf16LEBOM):
+		source.encoding = "utf-16le"
+		source.decoder = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder
+		return source, nil
+	case bytes.HasPrefix(bom, utf16BEBOM):
+		source.encoding = "utf-16be"
+		source.decoder = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder
+		return source, nil
+	}
+
+	valid, windows1252, err := scanText(file, size)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case valid:
+		source.encoding = "utf-8"
+	case windows1252:
+		// Los bytes 0x80–0x9F solo tienen caracteres imprimibles en Windows-1252
+		source.encoding = "windows-1252"
+		source.decoder = charmap.Windows1252.NewDecoder
+	default:
+		source.encoding = "iso-8859-1"
+		source.decoder = charmap.ISO8859_1.NewDecoder
+	}
+	return source, nil
+}
+
+// scanText indica si el archivo es UTF-8 válido y si contiene bytes 0x80–0x9F. Un carácter
+// partido entre dos bloques se completa con el bloque siguiente.
+func scanText(file io.ReaderAt, size int64) (bool, bool, error) {
+	valid, windows1252 := true, false
+	buffer := make([]byte, scanChunkSize)
+	carry := 0
+	for offset := int64(0); offset < size; {
+		n, err := file.ReadAt(buffer[carry:], offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, false, err
+		}
+		offset += int64(n)
+		chunk := buffer[:carry+n]
+
+		end := len(chunk)
+		if offset < size {
+			for i := 1; i < utf8.UTFMax && i <= len(chunk); i++ {
+				if utf8.RuneStart(chunk[len(chunk)-i]) {
+					if !utf8.FullRune(chunk[len(chunk)-i:]) {
+						end = len(chunk) - i
+					}
+					break
+				}
+			}
+		}
+
+		for _, b := range chunk[:end] {
+			if b >= 0x80 && b <= 0x9F {
+				windows1252 = true
+				break
+			}
+		}
+		if valid && !utf8.Valid(chunk[:end]) {
+			valid = false
+		}
+		carry = copy(buffer, chunk[end:])
+		if n == 0 {
+			break
+		}
+	}
+	return valid, windows1252, nil
+}
+
+// readSample lee el inicio del texto para detectar el delimitador, sin la última línea
+// si quedó incompleta
+func readSample(text io.Reader) (string, error) {
+	sample := make([]byte, sniffSampleSize)
+	n, err := io.ReadFull(text, sample)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return string(sample[:n]), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if last := bytes.LastIndexByte(sample, '\n'); last >= 0 {
+		n = last + 1
+	}
+	return string(sample[:n]), nil
+}
+
+// sniffDelimiter elige el delimitador que aparece un número constante de veces en las primeras líneas
+func sniffDelimiter(text string) rune {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == sniffLines {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return ','
+	}
+
+	best := ','
+	bestConsistency, bestCount := 0.0, 0
+	for _, delimiter := range candidateDelimiters {
+		first := countOutsideQuotes(lines[0], delimiter)
+		if first == 0 {
+			continue
+		}
+
+		matching := 0
+		for _, line := range lines {
+			if countOutsideQuotes(line, delimiter) == first {
+				matching++
+			}
+		}
+
+		consistency := float64(matching) / float64(len(lines))
+		if consistency > bestConsistency || (consistency == bestConsistency && first > bestCount) {
+			best, bestConsistency, bestCount = delimiter, consistency, first
+		}
+	}
+	return best
+}
+
+// countOutsideQuotes cuenta las apariciones de un delimitador fuera de campos entrecomillados
+func countOutsideQuotes(line string, delimiter rune) int {
+	count := 0
+	quoted := false
+	for _, char := range line {
+		switch {
+		case char == '"':
+			quoted = !quoted
+		case char == delimiter && !quoted:
+			count++
+		}
+	}
+	return count
+}
+
+type delimitedWorkbook struct {
+	format    entities.FileFormat
+	sheet     string
+	source    *textSource
+	delimiter rune
+}
+
+func (w *delimitedWorkbook) Format() entities.FileFormat {
+	return w.format
+}
+
+func (w *delimitedWorkbook) SheetList() []string {
+	return []string{w.sheet}
+}
+
+func (w *delimitedWorkbook) Rows(sheet string) ([][]string, error) {
+	iterator, err := w.StreamRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	var rows [][]string
+	for iterator.Next() {
+		row, err := iterator.Columns()
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// StreamRows lee las filas del archivo conforme avanza, sin cargarlo completo
+func (w *delimitedWorkbook) StreamRows(sheet string) (readers.RowIterator, error) {
+	if sheet != w.sheet {
+		return nil, errors.New("hoja no encontrada")
+	}
+
+	source := w.source
+	raw := &countingReader{reader: io.NewSectionReader(source.file, source.skip, source.size-source.skip)}
+	reader := csv.NewReader(source.decode(raw))
+	reader.Comma = w.delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	return &delimitedRows{reader: reader, raw: raw, size: source.size - source.skip, nextLine: 1}, nil
+}
+
+func (w *delimitedWorkbook) Close() error {
+	return nil
+}
+
+// countingReader cuenta los bytes leídos del archivo
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+// delimitedRows recorre los registros del archivo conservando las líneas vacías como
+// filas vacías, para que los números de fila coincidan con los del archivo original
+type delimitedRows struct {
+	reader   *csv.Reader
+	raw      *countingReader
+	size     int64
+	nextLine int
+	blank    int
+	pending  []string
+	current  []string
+	err      error
+}
+
+func (r *delimitedRows) Next() bool {
+	switch {
+	case r.err != nil:
+		return false
+	case r.blank > 0:
+		r.blank--
+		r.current = []string{}
+		return true
+	case r.pending != nil:
+		r.current, r.pending = r.pending, nil
+		return true
+	}
+
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+	if err != nil {
+		r.err = err
+		return true
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	lastLine, _ := r.reader.FieldPos(len(record) - 1)
+	blank := line - r.nextLine
+	r.nextLine = lastLine + strings.Count(record[len(record)-1], "\n") + 1
+	if blank > 0 {
+		r.blank, r.pending = blank-1, record
+		r.current = []string{}
+		return true
+	}
+	r.current = record
+	return true
+}
+
+func (r *delimitedRows) Columns() ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.current, nil
+}
+
+// ReadFraction retorna la fracción del archivo ya leída. El lector CSV lee por bloques,
+// así que puede ir unos kilobytes adelante de la fila actual.
+func (r *delimitedRows) ReadFraction() float64 {
+	if r.size <= 0 {
+		return 1
+	}
+	return float64(r.raw.read) / float64(r.size)
+}
+
+func (r *delimitedRows) Close() error {
+	return nil
+}
@@ -0,0 +1,177 @@
+package readers
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestDelimitedReaderOpen(t *testing.T) {
+	utf16LE, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("Clave,Nombre\n1,Zoé\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	utf16BE, err := unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("Clave\tNombre\n1\tZoé\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		fileName  string
+		content   []byte
+		format    string
+		delimiter string
+		encoding  string
+		rows      [][]string
+	}{
+		{
+			name:      "csv en utf-8",
+			fileName:  "contactos.csv",
+			content:   []byte("Clave,Nombre\n1,José\n"),
+			format:    "csv",
+			delimiter: ",",
+			encoding:  "utf-8",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "José"}},
+		},
+		{
+			name:      "utf-8 con BOM y punto y coma",
+			fileName:  "contactos.csv",
+			content:   append([]byte{0xEF, 0xBB, 0xBF}, "Clave;Nombre\r\n1;Ana\r\n"...),
+			format:    "csv",
+			delimiter: ";",
+			encoding:  "utf-8-bom",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "Ana"}},
+		},
+		{
+			name:      "latin-1",
+			fileName:  "contactos.csv",
+			content:   []byte("Clave,Nombre\n1,Jos\xe9 N\xfa\xf1ez\n"),
+			format:    "csv",
+			delimiter: ",",
+			encoding:  "iso-8859-1",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "José Núñez"}},
+		},
+		{
+			name:      "windows-1252",
+			fileName:  "contactos.csv",
+			content:   []byte("Clave,Nombre\n1,\x93Ana\x94\n"),
+			format:    "csv",
+			delimiter: ",",
+			encoding:  "windows-1252",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "“Ana”"}},
+		},
+		{
+			name:      "utf-16le con BOM",
+			fileName:  "contactos.csv",
+			content:   utf16LE,
+			format:    "csv",
+			delimiter: ",",
+			encoding:  "utf-16le",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "Zoé"}},
+		},
+		{
+			name:      "utf-16be con BOM y tabuladores",
+			fileName:  "contactos.txt",
+			content:   utf16BE,
+			format:    "tsv",
+			delimiter: "\t",
+			encoding:  "utf-16be",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "Zoé"}},
+		},
+		{
+			name:      "tsv por extensión aunque las comas sean más constantes",
+			fileName:  "contactos.tsv",
+			content:   []byte("Clave\tNombre\n1\tPérez, Ana\n2\tLópez, Beto\n"),
+			format:    "tsv",
+			delimiter: "\t",
+			encoding:  "utf-8",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "Pérez, Ana"}, {"2", "López, Beto"}},
+		},
+		{
+			name:      "delimitadores entre comillas no cuentan",
+			fileName:  "contactos.csv",
+			content:   []byte("Clave|Nombre\n1|\"Ana|Beto\"\n"),
+			format:    "csv",
+			delimiter: "|",
+			encoding:  "utf-8",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "Ana|Beto"}},
+		},
+		{
+			name:      "campo entrecomillado con salto de línea",
+			fileName:  "contactos.csv",
+			content:   []byte("Clave,Nombre\n1,\"Ana\nPeña\"\n"),
+			format:    "csv",
+			delimiter: ",",
+			encoding:  "utf-8",
+			rows:      [][]string{{"Clave", "Nombre"}, {"1", "Ana\nPeña"}},
+		},
+	}
+
+	reader := NewDelimitedReader()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workbook, err := reader.Open(tt.fileName, bytes.NewReader(tt.content), int64(len(tt.content)))
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer workbook.Close()
+
+			format := workbook.Format()
+			if format.Name != tt.format || format.Delimiter != tt.delimiter || format.Encoding != tt.encoding {
+				t.Errorf("Format() = %s %q %s, se esperaba %s %q %s", format.Name, format.Delimiter, format.Encoding, tt.format, tt.delimiter, tt.encoding)
+			}
+
+			sheets := workbook.SheetList()
+			if len(sheets) != 1 || sheets[0] != "contactos" {
+				t.Fatalf("SheetList() = %v, se esperaba [contactos]", sheets)
+			}
+			rows, err := workbook.Rows(sheets[0])
+			if err != nil {
+				t.Fatalf("Rows() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.rows) {
+				t.Errorf("Rows() = %q, se esperaba %q", rows, tt.rows)
+			}
+		})
+	}
+}
+
+func TestSniffDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want rune
+	}{
+		{name: "comas", text: "a,b,c\n1,2,3\n", want: ','},
+		{name: "punto y coma con comas decimales", text: "a;b;c\n1,5;2,25;3\n4;5,1;6\n", want: ';'},
+		{name: "tabuladores", text: "a\tb\n1\t2\n", want: '\t'},
+		{name: "barras", text: "a|b|c\n1|2|3\n", want: '|'},
+		{name: "sin delimitador", text: "nombre\nAna\n", want: ','},
+		{name: "texto vacío", text: "", want: ','},
+		{name: "líneas vacías al inicio", text: "\n\n a;b\n1;2\n", want: ';'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffDelimiter(tt.text); got != tt.want {
+				t.Errorf("sniffDelimiter(%q) = %q, se esperaba %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanTextAcrossChunks(t *testing.T) {
+	// Un carácter de dos bytes partido entre dos bloques sigue siendo UTF-8 válido
+	content := []byte(strings.Repeat("a", scanChunkSize-1) + "é\n")
+	valid, windows1252, err := scanText(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("scanText() error = %v", err)
+	}
+	if !valid || windows1252 {
+		t.Errorf("scanText() = %v, %v; se esperaba UTF-8 válido", valid, windows1252)
+	}
+}
@@ -0,0 +1,281 @@
+package readers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+)
+
+// Espacios de nombres de OpenDocument usados en content.xml
+const (
+	odsTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odsOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+)
+
+// odsMimeType es el tipo MIME que los archivos ODS guardan al inicio del contenedor ZIP
+const odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+
+// Límites de una hoja, iguales a los de Excel. Los atributos de repetición los controla el
+// archivo, así que un archivo pequeño podría pedir millones de filas o celdas sin ellos.
+const (
+	odsMaxRows     = 1048576
+	odsMaxColumns  = 16384
+	odsMaxCellText = 32767
+)
+
+type ODSReader struct{}
+
+// NewODSReader crea un lector de hojas de cálculo OpenDocument (.ods)
+func NewODSReader() readers.WorkbookReader {
+	return &ODSReader{}
+}
+
+// Name retorna el nombre del formato
+func (r *ODSReader) Name() string {
+	return "ods"
+}
+
+// Detect reconoce un contenedor ZIP con el tipo MIME de OpenDocument
+func (r *ODSReader) Detect(fileName string, head []byte) bool {
+	if !bytes.HasPrefix(head, zipMagic) {
+		return false
+	}
+	if strings.EqualFold(filepath.Ext(fileName), ".ods") {
+		return true
+	}
+
+	if len(head) > 128 {
+		head = head[:128]
+	}
+	return bytes.Contains(head, []byte(odsMimeType))
+}
+
+// Open lee todas las hojas de content.xml
+func (r *ODSReader) Open(fileName string, file io.ReaderAt, size int64) (readers.Workbook, error) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range archive.File {
+		if file.Name != "content.xml" {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer content.Close()
+
+		return parseODSContent(content)
+	}
+
+	return nil, errors.New("el archivo ODS no contiene content.xml")
+}
+
+// parseODSContent recorre content.xml y construye las filas de cada tabla. Las filas y
+// celdas vacías repetidas al final de una tabla se descartan, igual que en el lector XLSX;
+// las repeticiones se recortan a los límites de la hoja y las filas repetidas comparten
+// sus celdas.
+func parseODSContent(content io.Reader) (*odsWorkbook, error) {
+	workbook := &odsWorkbook{rows: make(map[string][][]string)}
+	decoder := xml.NewDecoder(content)
+
+	var (
+		sheet        string
+		rows         [][]string
+		pendingRows  int
+		row          []string
+		rowRepeat    int
+		pendingCells int
+		inCell       bool
+		cellText     strings.Builder
+		cellValue    string
+		cellRepeat   int
+		paragraphs   int
+		inAnnotation bool
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				sheet = odsAttr(t, odsTableNS, "name")
+				if _, exists := workbook.rows[sheet]; exists {
+					return nil, fmt.Errorf("el archivo ODS tiene más de una hoja llamada '%s'", sheet)
+				}
+				rows, pendingRows = nil, 0
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				row, pendingCells = nil, 0
+				rowRepeat = min(odsRepeat(t, "number-rows-repeated"), odsMaxRows)
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = true
+				cellText.Reset()
+				paragraphs = 0
+				cellRepeat = min(odsRepeat(t, "number-columns-repeated"), odsMaxColumns)
+				cellValue = odsCellValue(t)
+			case inCell && t.Name.Space == odsOfficeNS && t.Name.Local == "annotation":
+				inAnnotation = true
+			case inCell && !inAnnotation && t.Name.Space == odsTextNS:
+				switch t.Name.Local {
+				case "p":
+					if paragraphs > 0 {
+						cellText.WriteString("\n")
+					}
+					paragraphs++
+				case "s":
+					cellText.WriteString(strings.Repeat(" ", max(0, min(odsRepeat(t, "c"), odsMaxCellText-cellText.Len()))))
+				case "tab":
+					cellText.WriteString("\t")
+				case "line-break":
+					cellText.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if inCell && !inAnnotation && paragraphs > 0 && cellText.Len() < odsMaxCellText {
+				cellText.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == odsOfficeNS && t.Name.Local == "annotation":
+				inAnnotation = false
+			case t.Name.Space == odsTableNS && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = false
+				value := cellText.String()
+				if value == "" {
+					value = cellValue
+				}
+				if value == "" {
+					pendingCells += cellRepeat
+					continue
+				}
+				for ; pendingCells > 0 && len(row) < odsMaxColumns; pendingCells-- {
+					row = append(row, "")
+				}
+				pendingCells = 0
+				for i := 0; i < cellRepeat && len(row) < odsMaxColumns; i++ {
+					row = append(row, value)
+				}
+			case t.Name.Space == odsTableNS && t.Name.Local == "table-row":
+				if len(row) == 0 {
+					pendingRows += rowRepeat
+					continue
+				}
+				for ; pendingRows > 0 && len(rows) < odsMaxRows; pendingRows-- {
+					rows = append(rows, []string{})
+				}
+				pendingRows = 0
+				for i := 0; i < rowRepeat && len(rows) < odsMaxRows; i++ {
+					rows = append(rows, row)
+				}
+			case t.Name.Space == odsTableNS && t.Name.Local == "table":
+				workbook.sheets = append(workbook.sheets, sheet)
+				workbook.rows[sheet] = rows
+			}
+		}
+	}
+
+	return workbook, nil
+}
+
+// odsCellValue obtiene el valor tipado de una celda, usado cuando no tiene texto visible
+func odsCellValue(element xml.StartElement) string {
+	switch odsAttr(element, odsOfficeNS, "value-type") {
+	case "float", "percentage", "currency":
+		return odsAttr(element, odsOfficeNS, "value")
+	case "date":
+		return odsAttr(element, odsOfficeNS, "date-value")
+	case "time":
+		return odsAttr(element, odsOfficeNS, "time-value")
+	case "boolean":
+		return strings.ToUpper(odsAttr(element, odsOfficeNS, "boolean-value"))
+	case "string":
+		return odsAttr(element, odsOfficeNS, "string-value")
+	}
+	return ""
+}
+
+// odsRepeat lee un atributo de repetición; por defecto es 1
+func odsRepeat(element xml.StartElement, name string) int {
+	space := odsTableNS
+	if name == "c" {
+		space = odsTextNS
+	}
+
+	repeat, err := strconv.Atoi(odsAttr(element, space, name))
+	if err != nil || repeat < 1 {
+		return 1
+	}
+	return repeat
+}
+
+func odsAttr(element xml.StartElement, space, local string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+type odsWorkbook struct {
+	sheets []string
+	rows   map[string][][]string
+}
+
+func (w *odsWorkbook) Format() entities.FileFormat {
+	return entities.FileFormat{Name: "ods"}
+}
+
+func (w *odsWorkbook) SheetList() []string {
+	return w.sheets
+}
+
+func (w *odsWorkbook) Rows(sheet string) ([][]string, error) {
+	rows, exists := w.rows[sheet]
+	if !exists {
+		return nil, errors.New("hoja no encontrada")
+	}
+	return rows, nil
+}
+
+// RowCount obtiene el número de filas de la hoja, que ya está cargada, hasta la última
+// con datos
+func (w *odsWorkbook) RowCount(sheet string) (int, bool) {
+	rows, exists := w.rows[sheet]
+	return dataRowCount(rows), exists
+}
+
+func (w *odsWorkbook) Close() error {
+	return nil
+}
+
+// dataRowCount cuenta las filas después del header hasta la última con datos
+func dataRowCount(rows [][]string) int {
+	for last := len(rows) - 1; last > 0; last-- {
+		if len(rows[last]) > 0 {
+			return last
+		}
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package readers
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// odsFile arma un archivo ODS con el cuerpo de content.xml indicado
+func odsFile(t *testing.T, body string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	files := []struct{ name, content string }{
+		{"mimetype", odsMimeType},
+		{"content.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="` + odsOfficeNS + `" xmlns:table="` + odsTableNS + `" xmlns:text="` + odsTextNS + `">
+<office:body><office:spreadsheet>` + body + `</office:spreadsheet></office:body></office:document-content>`},
+	}
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestODSReaderOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		sheets []string
+		rows   map[string][][]string
+		err    string
+	}{
+		{
+			name: "texto y valores tipados",
+			body: `<table:table table:name="Contactos">
+<table:table-row><table:table-cell><text:p>Clave</text:p></table:table-cell><table:table-cell><text:p>Nombre</text:p></table:table-cell></table:table-row>
+<table:table-row><table:table-cell office:value-type="float" office:value="1234"/><table:table-cell office:value-type="string" office:string-value="Ana"/></table:table-row>
+</table:table>`,
+			sheets: []string{"Contactos"},
+			rows:   map[string][][]string{"Contactos": {{"Clave", "Nombre"}, {"1234", "Ana"}}},
+		},
+		{
+			name: "espacios, párrafos y comentarios",
+			body: `<table:table table:name="Hoja1">
+<table:table-row><table:table-cell><text:p>Ana<text:s text:c="2"/>Peña</text:p><text:p>Ruiz</text:p><office:annotation><text:p>nota</text:p></office:annotation></table:table-cell></table:table-row>
+</table:table>`,
+			sheets: []string{"Hoja1"},
+			rows:   map[string][][]string{"Hoja1": {{"Ana  Peña\nRuiz"}}},
+		},
+		{
+			name: "repeticiones y celdas vacías al final",
+			body: `<table:table table:name="Hoja1">
+<table:table-row><table:table-cell table:number-columns-repeated="2"><text:p>x</text:p></table:table-cell><table:table-cell/><table:table-cell><text:p>y</text:p></table:table-cell><table:table-cell table:number-columns-repeated="16000"/></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell/></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell><text:p>z</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1048000"><table:table-cell/></table:table-row>
+</table:table>`,
+			sheets: []string{"Hoja1"},
+			rows:   map[string][][]string{"Hoja1": {{"x", "x", "", "y"}, {}, {}, {"z"}, {"z"}}},
+		},
+		{
+			name: "varias hojas en orden",
+			body: `<table:table table:name="B"><table:table-row><table:table-cell><text:p>1</text:p></table:table-cell></table:table-row></table:table>
+<table:table table:name="A"><table:table-row><table:table-cell><text:p>2</text:p></table:table-cell></table:table-row></table:table>`,
+			sheets: []string{"B", "A"},
+			rows:   map[string][][]string{"B": {{"1"}}, "A": {{"2"}}},
+		},
+		{
+			name: "hojas con el mismo nombre",
+			body: `<table:table table:name="A"></table:table><table:table table:name="A"></table:table>`,
+			err:  "más de una hoja",
+		},
+	}
+
+	reader := NewODSReader()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := odsFile(t, tt.body)
+			if !reader.Detect("contactos.bin", content) {
+				t.Fatalf("Detect() no reconoció el archivo ODS por su tipo MIME")
+			}
+
+			workbook, err := reader.Open("contactos.ods", bytes.NewReader(content), int64(len(content)))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Open() error = %v, se esperaba %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+
+			if sheets := workbook.SheetList(); !reflect.DeepEqual(sheets, tt.sheets) {
+				t.Errorf("SheetList() = %v, se esperaba %v", sheets, tt.sheets)
+			}
+			for sheet, want := range tt.rows {
+				rows, err := workbook.Rows(sheet)
+				if err != nil {
+					t.Fatalf("Rows(%s) error = %v", sheet, err)
+				}
+				if !reflect.DeepEqual(rows, want) {
+					t.Errorf("Rows(%s) = %q, se esperaba %q", sheet, rows, want)
+				}
+			}
+		})
+	}
+}
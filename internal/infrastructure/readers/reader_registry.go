@@ -0,0 +1,37 @@
+package readers
+
+import (
+	"errors"
+	"io"
+
+	"analizador-backend/internal/domain/readers"
+)
+
+// detectHeadSize es el número de bytes del inicio del archivo con los que se detecta su formato
+const detectHeadSize = 8192
+
+type ReaderRegistry struct {
+	readers []readers.WorkbookReader
+}
+
+// NewReaderRegistry crea un registro de lectores; el orden define la prioridad de detección
+func NewReaderRegistry(workbookReaders ...readers.WorkbookReader) readers.WorkbookOpener {
+	return &ReaderRegistry{
+		readers: workbookReaders,
+	}
+}
+
+// Open abre el archivo con el primer lector que reconozca su formato
+func (r *ReaderRegistry) Open(fileName string, file io.ReaderAt, size int64) (readers.Workbook, error) {
+	head := make([]byte, min(size, detectHeadSize))
+	if _, err := file.ReadAt(head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for _, reader := range r.readers {
+		if reader.Detect(fileName, head) {
+			return reader.Open(fileName, file, size)
+		}
+	}
+	return nil, errors.New("formato de archivo no soportado")
+}
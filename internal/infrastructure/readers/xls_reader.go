@@ -0,0 +1,534 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+)
+
+// oleMagic es la firma de los archivos compuestos OLE2 usados por Excel 97-2003
+var oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// Tipos de registro BIFF8 que se interpretan
+const (
+	biffFormula    = 0x0006
+	biffEOF        = 0x000A
+	biffFilePass   = 0x002F
+	biffContinue   = 0x003C
+	biffBoundSheet = 0x0085
+	biffMulRK      = 0x00BD
+	biffSST        = 0x00FC
+	biffLabelSST   = 0x00FD
+	biffNumber     = 0x0203
+	biffLabel      = 0x0204
+	biffBoolErr    = 0x0205
+	biffString     = 0x0207
+	biffRK         = 0x027E
+	biffBOF        = 0x0809
+)
+
+// biff8Version es la versión del registro BOF de Excel 97-2003
+const biff8Version = 0x0600
+
+// biffErrorValues traduce los códigos de error de celda a su texto en Excel
+var biffErrorValues = map[byte]string{
+	0x00: "#NULL!", 0x07: "#DIV/0!", 0x0F: "#VALUE!", 0x17: "#REF!",
+	0x1D: "#NAME?", 0x24: "#NUM!", 0x2A: "#N/A",
+}
+
+type XLSReader struct{}
+
+// NewXLSReader crea un lector de libros de Excel 97-2003 (.xls, BIFF8)
+func NewXLSReader() readers.WorkbookReader {
+	return &XLSReader{}
+}
+
+// Name retorna el nombre del formato
+func (r *XLSReader) Name() string {
+	return "xls"
+}
+
+// Detect reconoce un archivo compuesto OLE2
+func (r *XLSReader) Detect(fileName string, head []byte) bool {
+	return bytes.HasPrefix(head, oleMagic)
+}
+
+// Open extrae el flujo Workbook del archivo compuesto y lee sus hojas de trabajo.
+// Los números se devuelven sin formato (las fechas quedan como número de serie).
+func (r *XLSReader) Open(fileName string, file io.ReaderAt, size int64) (readers.Workbook, error) {
+	doc, err := mscfb.New(io.NewSectionReader(file, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	var stream []byte
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" {
+			stream, err = io.ReadAll(entry)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+		if entry.Name == "Book" {
+			return nil, errors.New("solo se admiten archivos de Excel 97-2003 (BIFF8)")
+		}
+	}
+	if stream == nil {
+		if strings.EqualFold(filepath.Ext(fileName), ".xls") {
+			return nil, errors.New("el archivo XLS no contiene un libro de trabajo")
+		}
+		return nil, errors.New("el archivo no es un libro de Excel")
+	}
+
+	return parseBIFF8(stream)
+}
+
+type biffRecord struct {
+	kind   uint16
+	data   []byte
+	offset int
+}
+
+// readRecord lee el registro que inicia en la posición indicada del flujo
+func readRecord(stream []byte, offset int) (biffRecord, int, error) {
+	if offset+4 > len(stream) {
+		return biffRecord{}, 0, io.ErrUnexpectedEOF
+	}
+
+	kind := binary.LittleEndian.Uint16(stream[offset:])
+	size := int(binary.LittleEndian.Uint16(stream[offset+2:]))
+	end := offset + 4 + size
+	if end > len(stream) {
+		return biffRecord{}, 0, io.ErrUnexpectedEOF
+	}
+
+	return biffRecord{kind: kind, data: stream[offset+4 : end], offset: offset}, end, nil
+}
+
+// parseBIFF8 lee el subflujo global (hojas y tabla de cadenas) y luego cada hoja
+func parseBIFF8(stream []byte) (*xlsWorkbook, error) {
+	type boundSheet struct {
+		name   string
+		offset int
+	}
+
+	var (
+		sheets []boundSheet
+		sst    []string
+	)
+
+	offset := 0
+	for offset < len(stream) {
+		record, next, err := readRecord(stream, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+
+		switch record.kind {
+		case biffBOF:
+			if len(record.data) >= 2 && binary.LittleEndian.Uint16(record.data) != biff8Version {
+				return nil, errors.New("solo se admiten archivos de Excel 97-2003 (BIFF8)")
+			}
+		case biffFilePass:
+			return nil, errors.New("el archivo XLS está protegido con contraseña")
+		case biffBoundSheet:
+			// Solo hojas de trabajo (dt = 0), no gráficos ni macros
+			if len(record.data) < 8 || record.data[5] != 0 {
+				continue
+			}
+			name, _ := readShortString(record.data[6:])
+			sheets = append(sheets, boundSheet{
+				name:   name,
+				offset: int(binary.LittleEndian.Uint32(record.data)),
+			})
+		case biffSST:
+			chunks := [][]byte{record.data}
+			for offset < len(stream) {
+				continuation, after, err := readRecord(stream, offset)
+				if err != nil || continuation.kind != biffContinue {
+					break
+				}
+				chunks = append(chunks, continuation.data)
+				offset = after
+			}
+			sst, err = readSST(chunks)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if record.kind == biffEOF {
+			break
+		}
+	}
+
+	workbook := &xlsWorkbook{rows: make(map[string][][]string)}
+	for _, sheet := range sheets {
+		if _, exists := workbook.rows[sheet.name]; exists {
+			return nil, fmt.Errorf("el archivo XLS tiene más de una hoja llamada '%s'", sheet.name)
+		}
+		rows, err := parseBIFF8Sheet(stream, sheet.offset, sst)
+		if err != nil {
+			return nil, err
+		}
+		workbook.sheets = append(workbook.sheets, sheet.name)
+		workbook.rows[sheet.name] = rows
+	}
+
+	return workbook, nil
+}
+
+// parseBIFF8Sheet lee las celdas del subflujo de una hoja
+func parseBIFF8Sheet(stream []byte, offset int, sst []string) ([][]string, error) {
+	cells := make(map[int]map[int]string)
+	maxRow := -1
+	set := func(row, col int, value string) {
+		if value == "" {
+			return
+		}
+		if cells[row] == nil {
+			cells[row] = make(map[int]string)
+		}
+		cells[row][col] = value
+		if row > maxRow {
+			maxRow = row
+		}
+	}
+
+	pendingFormula := [2]int{-1, -1}
+	for offset < len(stream) {
+		record, next, err := readRecord(stream, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = next
+		data := record.data
+
+		switch record.kind {
+		case biffLabelSST:
+			if len(data) >= 10 {
+				index := int(binary.LittleEndian.Uint32(data[6:]))
+				if index < len(sst) {
+					set(cellRef(data), cellCol(data), sst[index])
+				}
+			}
+		case biffLabel:
+			if len(data) >= 9 {
+				value, _ := readUnicodeString(data[6:])
+				set(cellRef(data), cellCol(data), value)
+			}
+		case biffNumber:
+			if len(data) >= 14 {
+				value := math.Float64frombits(binary.LittleEndian.Uint64(data[6:]))
+				set(cellRef(data), cellCol(data), formatNumber(value))
+			}
+		case biffRK:
+			if len(data) >= 10 {
+				set(cellRef(data), cellCol(data), formatNumber(decodeRK(binary.LittleEndian.Uint32(data[6:]))))
+			}
+		case biffMulRK:
+			if len(data) >= 6 {
+				row := cellRef(data)
+				col := cellCol(data)
+				for pos := 4; pos+6 <= len(data)-2; pos += 6 {
+					set(row, col, formatNumber(decodeRK(binary.LittleEndian.Uint32(data[pos+2:]))))
+					col++
+				}
+			}
+		case biffBoolErr:
+			if len(data) >= 8 {
+				value := data[6]
+				if data[7] == 1 {
+					set(cellRef(data), cellCol(data), biffErrorValues[value])
+				} else if value == 1 {
+					set(cellRef(data), cellCol(data), "TRUE")
+				} else {
+					set(cellRef(data), cellCol(data), "FALSE")
+				}
+			}
+		case biffFormula:
+			if len(data) < 14 {
+				continue
+			}
+			row, col := cellRef(data), cellCol(data)
+			result := data[6:14]
+			if result[6] != 0xFF || result[7] != 0xFF {
+				set(row, col, formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(result))))
+				continue
+			}
+			switch result[0] {
+			case 0x00:
+				// El texto del resultado llega en el registro STRING siguiente
+				pendingFormula = [2]int{row, col}
+			case 0x01:
+				if result[2] == 1 {
+					set(row, col, "TRUE")
+				} else {
+					set(row, col, "FALSE")
+				}
+			case 0x02:
+				set(row, col, biffErrorValues[result[2]])
+			}
+		case biffString:
+			if pendingFormula[0] >= 0 {
+				value, _ := readUnicodeString(data)
+				set(pendingFormula[0], pendingFormula[1], value)
+				pendingFormula = [2]int{-1, -1}
+			}
+		case biffEOF:
+			return buildRows(cells, maxRow), nil
+		}
+	}
+
+	return buildRows(cells, maxRow), nil
+}
+
+// buildRows convierte las celdas dispersas en filas, descartando celdas vacías al final de cada fila
+func buildRows(cells map[int]map[int]string, maxRow int) [][]string {
+	rows := make([][]string, maxRow+1)
+	for rowIndex := range rows {
+		maxCol := -1
+		for col := range cells[rowIndex] {
+			if col > maxCol {
+				maxCol = col
+			}
+		}
+
+		row := make([]string, maxCol+1)
+		for col, value := range cells[rowIndex] {
+			row[col] = value
+		}
+		rows[rowIndex] = row
+	}
+	return rows
+}
+
+func cellRef(data []byte) int {
+	return int(binary.LittleEndian.Uint16(data))
+}
+
+func cellCol(data []byte) int {
+	return int(binary.LittleEndian.Uint16(data[2:]))
+}
+
+// decodeRK decodifica un número RK: entero de 30 bits o flotante truncado, opcionalmente dividido entre 100
+func decodeRK(rk uint32) float64 {
+	var value float64
+	if rk&0x02 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// readShortString lee una cadena con longitud de 1 byte (ShortXLUnicodeString)
+func readShortString(data []byte) (string, int) {
+	if len(data) < 2 {
+		return "", len(data)
+	}
+	return readChars(data[2:], int(data[0]), data[1]&0x01 != 0)
+}
+
+// readUnicodeString lee una cadena con longitud de 2 bytes (XLUnicodeString)
+func readUnicodeString(data []byte) (string, int) {
+	if len(data) < 3 {
+		return "", len(data)
+	}
+	return readChars(data[3:], int(binary.LittleEndian.Uint16(data)), data[2]&0x01 != 0)
+}
+
+// readChars lee caracteres comprimidos (1 byte, Latin-1) o UTF-16LE y retorna los bytes consumidos
+func readChars(data []byte, count int, highByte bool) (string, int) {
+	if highByte {
+		if count*2 > len(data) {
+			count = len(data) / 2
+		}
+		units := make([]uint16, count)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(data[i*2:])
+		}
+		return string(utf16.Decode(units)), count * 2
+	}
+
+	if count > len(data) {
+		count = len(data)
+	}
+	runes := make([]rune, count)
+	for i := range runes {
+		runes[i] = rune(data[i])
+	}
+	return string(runes), count
+}
+
+// sstReader recorre la tabla de cadenas compartidas, que puede continuar en registros CONTINUE
+type sstReader struct {
+	chunks [][]byte
+	chunk  int
+	pos    int
+}
+
+func (r *sstReader) available() int {
+	if r.chunk >= len(r.chunks) {
+		return 0
+	}
+	return len(r.chunks[r.chunk]) - r.pos
+}
+
+// advance pasa al siguiente registro cuando el actual se agotó
+func (r *sstReader) advance() bool {
+	for r.available() == 0 {
+		if r.chunk >= len(r.chunks)-1 {
+			return false
+		}
+		r.chunk++
+		r.pos = 0
+	}
+	return true
+}
+
+func (r *sstReader) bytes(n int) []byte {
+	var out []byte
+	for n > 0 && r.advance() {
+		take := min(n, r.available())
+		out = append(out, r.chunks[r.chunk][r.pos:r.pos+take]...)
+		r.pos += take
+		n -= take
+	}
+	return out
+}
+
+func (r *sstReader) skip(n int) {
+	r.bytes(n)
+}
+
+// remaining cuenta los bytes que faltan por leer en todos los registros
+func (r *sstReader) remaining() int {
+	total := r.available()
+	for chunk := r.chunk + 1; chunk < len(r.chunks); chunk++ {
+		total += len(r.chunks[chunk])
+	}
+	return total
+}
+
+// errTruncatedSST indica que la tabla de cadenas termina antes de lo que declara
+var errTruncatedSST = errors.New("la tabla de cadenas del archivo XLS está incompleta")
+
+// readSST lee todas las cadenas de la tabla. Cuando una cadena se parte entre
+// registros, el registro CONTINUE inicia con un nuevo byte de opciones.
+func readSST(chunks [][]byte) ([]string, error) {
+	reader := &sstReader{chunks: chunks}
+	header := reader.bytes(8)
+	if len(header) < 8 {
+		return nil, errTruncatedSST
+	}
+
+	// El número de cadenas lo declara el archivo: cada cadena ocupa al menos 3 bytes, así
+	// que la capacidad no puede pasar de lo que realmente queda por leer
+	unique := int(binary.LittleEndian.Uint32(header[4:]))
+	values := make([]string, 0, min(unique, reader.remaining()/3))
+	for i := 0; i < unique; i++ {
+		head := reader.bytes(3)
+		if len(head) < 3 {
+			break
+		}
+		count := int(binary.LittleEndian.Uint16(head))
+		flags := head[2]
+
+		runs := 0
+		if flags&0x08 != 0 {
+			field := reader.bytes(2)
+			if len(field) < 2 {
+				return nil, errTruncatedSST
+			}
+			runs = int(binary.LittleEndian.Uint16(field))
+		}
+		extSize := 0
+		if flags&0x04 != 0 {
+			field := reader.bytes(4)
+			if len(field) < 4 {
+				return nil, errTruncatedSST
+			}
+			extSize = int(binary.LittleEndian.Uint32(field))
+		}
+
+		var builder strings.Builder
+		highByte := flags&0x01 != 0
+		for count > 0 && reader.advance() {
+			charSize := 1
+			if highByte {
+				charSize = 2
+			}
+			take := min(count, reader.available()/charSize)
+			if take == 0 {
+				break
+			}
+			text, _ := readChars(reader.chunks[reader.chunk][reader.pos:], take, highByte)
+			builder.WriteString(text)
+			reader.pos += take * charSize
+			count -= take
+
+			if count > 0 && reader.available() == 0 && reader.advance() {
+				highByte = reader.chunks[reader.chunk][reader.pos]&0x01 != 0
+				reader.pos++
+			}
+		}
+
+		reader.skip(runs*4 + extSize)
+		values = append(values, builder.String())
+	}
+
+	return values, nil
+}
+
+type xlsWorkbook struct {
+	sheets []string
+	rows   map[string][][]string
+}
+
+func (w *xlsWorkbook) Format() entities.FileFormat {
+	return entities.FileFormat{Name: "xls"}
+}
+
+func (w *xlsWorkbook) SheetList() []string {
+	return w.sheets
+}
+
+func (w *xlsWorkbook) Rows(sheet string) ([][]string, error) {
+	rows, exists := w.rows[sheet]
+	if !exists {
+		return nil, errors.New("hoja no encontrada")
+	}
+	return rows, nil
+}
+
+// RowCount obtiene el número de filas de la hoja, que ya está cargada, hasta la última
+// con datos
+func (w *xlsWorkbook) RowCount(sheet string) (int, bool) {
+	rows, exists := w.rows[sheet]
+	return dataRowCount(rows), exists
+}
+
+func (w *xlsWorkbook) Close() error {
+	return nil
+}
@@ -0,0 +1,191 @@
+package readers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// biffWriter arma un flujo Workbook BIFF8 registro por registro
+type biffWriter struct {
+	bytes.Buffer
+}
+
+func (w *biffWriter) record(kind uint16, data ...[]byte) {
+	body := bytes.Join(data, nil)
+	binary.Write(w, binary.LittleEndian, kind)
+	binary.Write(w, binary.LittleEndian, uint16(len(body)))
+	w.Write(body)
+}
+
+func u16(value int) []byte {
+	return binary.LittleEndian.AppendUint16(nil, uint16(value))
+}
+
+func u32(value int) []byte {
+	return binary.LittleEndian.AppendUint32(nil, uint32(value))
+}
+
+// cell arma el inicio de un registro de celda: fila, columna e índice de formato
+func cell(row, col int) []byte {
+	return bytes.Join([][]byte{u16(row), u16(col), u16(0)}, nil)
+}
+
+// compressed arma una cadena XLUnicodeString de caracteres de 1 byte
+func compressed(text string) []byte {
+	return append(append(u16(len(text)), 0), text...)
+}
+
+// biffBook arma un libro con una hoja por cada función, que escribe las celdas de la hoja
+func biffBook(sst []string, sheets map[string]func(w *biffWriter), order []string) []byte {
+	var globals biffWriter
+	globals.record(biffBOF, u16(biff8Version), u16(0x0005))
+	// Las posiciones de las hojas se completan después de escribir los registros globales
+	var positions []int
+	for _, name := range order {
+		positions = append(positions, globals.Len()+4)
+		globals.record(biffBoundSheet, u32(0), []byte{0, 0}, []byte{byte(len(name)), 0}, []byte(name))
+	}
+	var table []byte
+	for _, text := range sst {
+		table = append(table, compressed(text)...)
+	}
+	globals.record(biffSST, u32(len(sst)), u32(len(sst)), table)
+	globals.record(biffEOF)
+
+	stream := globals.Bytes()
+	for i, name := range order {
+		binary.LittleEndian.PutUint32(stream[positions[i]:], uint32(len(stream)))
+		var sheet biffWriter
+		sheet.record(biffBOF, u16(biff8Version), u16(0x0010))
+		sheets[name](&sheet)
+		sheet.record(biffEOF)
+		stream = append(stream, sheet.Bytes()...)
+	}
+	return stream
+}
+
+func TestParseBIFF8(t *testing.T) {
+	number := binary.LittleEndian.AppendUint64(nil, math.Float64bits(9611234567))
+	stringResult := []byte{0x00, 0, 0, 0, 0, 0, 0xFF, 0xFF}
+	errorResult := []byte{0x02, 0, 0x17, 0, 0, 0, 0xFF, 0xFF}
+
+	tests := []struct {
+		name   string
+		sst    []string
+		order  []string
+		sheets map[string]func(w *biffWriter)
+		rows   map[string][][]string
+		err    string
+	}{
+		{
+			name:  "cadenas compartidas, números y fórmulas",
+			sst:   []string{"Clave", "Nombre"},
+			order: []string{"Contactos"},
+			sheets: map[string]func(w *biffWriter){
+				"Contactos": func(w *biffWriter) {
+					w.record(biffLabelSST, cell(0, 0), u32(0))
+					w.record(biffLabelSST, cell(0, 1), u32(1))
+					w.record(biffLabelSST, cell(0, 3), u32(1))
+					// RK entero 1234 y un número de coma flotante
+					w.record(biffRK, cell(1, 0), u32(1234<<2|0x02))
+					w.record(biffNumber, cell(1, 3), number)
+					w.record(biffLabel, cell(1, 1), compressed("Jos\xe9"))
+					// Fórmula con texto en el registro STRING siguiente y fórmula con error
+					w.record(biffFormula, cell(2, 1), stringResult, make([]byte, 6))
+					w.record(biffString, compressed("Ana"))
+					w.record(biffFormula, cell(2, 2), errorResult, make([]byte, 6))
+				},
+			},
+			rows: map[string][][]string{"Contactos": {
+				{"Clave", "Nombre", "", "Nombre"},
+				{"1234", "José", "", "9611234567"},
+				{"", "Ana", "#REF!"},
+			}},
+		},
+		{
+			name:  "MULRK, booleanos y filas vacías",
+			order: []string{"A", "B"},
+			sheets: map[string]func(w *biffWriter){
+				"A": func(w *biffWriter) {
+					w.record(biffMulRK, u16(0), u16(1), u16(0), u32(5<<2|0x02), u16(0), u32(250<<2|0x03), u16(2))
+				},
+				"B": func(w *biffWriter) {
+					w.record(biffBoolErr, cell(2, 0), []byte{1, 0})
+				},
+			},
+			rows: map[string][][]string{
+				"A": {{"", "5", "2.5"}},
+				"B": {{}, {}, {"TRUE"}},
+			},
+		},
+		{
+			name:  "hojas con el mismo nombre",
+			order: []string{"A", "A"},
+			sheets: map[string]func(w *biffWriter){
+				"A": func(w *biffWriter) {},
+			},
+			err: "más de una hoja",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workbook, err := parseBIFF8(biffBook(tt.sst, tt.sheets, tt.order))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("parseBIFF8() error = %v, se esperaba %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBIFF8() error = %v", err)
+			}
+
+			if sheets := workbook.SheetList(); !reflect.DeepEqual(sheets, tt.order) {
+				t.Errorf("SheetList() = %v, se esperaba %v", sheets, tt.order)
+			}
+			for sheet, want := range tt.rows {
+				rows, err := workbook.Rows(sheet)
+				if err != nil {
+					t.Fatalf("Rows(%s) error = %v", sheet, err)
+				}
+				if !reflect.DeepEqual(rows, want) {
+					t.Errorf("Rows(%s) = %q, se esperaba %q", sheet, rows, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadSSTAcrossContinue(t *testing.T) {
+	// La segunda cadena se parte entre el registro SST y un CONTINUE que la sigue en UTF-16
+	first := bytes.Join([][]byte{u32(2), u32(2), compressed("Ana"), u16(4), {0}, []byte("Pe")}, nil)
+	second := []byte{0x01, 0xF1, 0x00, 'a', 0x00}
+
+	values, err := readSST([][]byte{first, second})
+	if err != nil {
+		t.Fatalf("readSST() error = %v", err)
+	}
+	if want := []string{"Ana", "Peña"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("readSST() = %q, se esperaba %q", values, want)
+	}
+
+	if _, err := readSST([][]byte{{1, 0}}); err == nil {
+		t.Errorf("readSST() de una tabla incompleta no retornó error")
+	}
+}
+
+func TestXLSReaderRejectsOtherFiles(t *testing.T) {
+	reader := NewXLSReader()
+	content := []byte("Clave,Nombre\n1,Ana\n")
+	if reader.Detect("contactos.xls", content) {
+		t.Errorf("Detect() reconoció un archivo de texto como XLS")
+	}
+	if _, err := reader.Open("contactos.xls", bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Errorf("Open() de un archivo de texto no retornó error")
+	}
+}
@@ -0,0 +1,252 @@
+package readers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/readers"
+)
+
+// zipMagic es la firma de los archivos ZIP (XLSX y ODS son contenedores ZIP)
+var zipMagic = []byte("PK\x03\x04")
+
+// xlsxSampleSize es el máximo de bytes del XML de una hoja que se leen para estimar su
+// número de filas cuando el archivo no guarda su dimensión
+const xlsxSampleSize = 256 * 1024
+
+// Partes del libro que relacionan el nombre de cada hoja con su archivo XML
+const (
+	xlsxWorkbookPart = "xl/workbook.xml"
+	xlsxWorkbookRels = "xl/_rels/workbook.xml.rels"
+)
+
+type XLSXReader struct{}
+
+// NewXLSXReader crea un lector de archivos XLSX
+func NewXLSXReader() readers.WorkbookReader {
+	return &XLSXReader{}
+}
+
+// Name retorna el nombre del formato
+func (r *XLSXReader) Name() string {
+	return "xlsx"
+}
+
+// Detect reconoce un contenedor ZIP con la estructura de un libro de Excel
+func (r *XLSXReader) Detect(fileName string, head []byte) bool {
+	if !bytes.HasPrefix(head, zipMagic) {
+		return false
+	}
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx", ".xlsm", ".xltx", ".xltm":
+		return true
+	}
+	return bytes.Contains(head, []byte("[Content_Types].xml"))
+}
+
+// Open abre el libro con excelize, que conserva el archivo comprimido en memoria y pasa a
+// archivos temporales las hojas que superan su límite de XML descomprimido
+func (r *XLSXReader) Open(fileName string, file io.ReaderAt, size int64) (readers.Workbook, error) {
+	f, err := excelize.OpenReader(io.NewSectionReader(file, 0, size))
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWorkbook{file: f, source: file, size: size}, nil
+}
+
+type xlsxWorkbook struct {
+	file *excelize.File
+	// source es el archivo original, del que se lee la dimensión de las hojas
+	source io.ReaderAt
+	size   int64
+}
+
+func (w *xlsxWorkbook) Format() entities.FileFormat {
+	return entities.FileFormat{Name: "xlsx"}
+}
+
+func (w *xlsxWorkbook) SheetList() []string {
+	return w.file.GetSheetList()
+}
+
+func (w *xlsxWorkbook) Rows(sheet string) ([][]string, error) {
+	return w.file.GetRows(sheet)
+}
+
+// StreamRows recorre la hoja con el iterador de excelize, que lee el XML de la hoja
+// conforme avanza en lugar de cargar todas las filas
+func (w *xlsxWorkbook) StreamRows(sheet string) (readers.RowIterator, error) {
+	rows, err := w.file.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxRows{rows: rows}, nil
+}
+
+func (w *xlsxWorkbook) Close() error {
+	return w.file.Close()
+}
+
+// RowCount obtiene el número de filas de la hoja sin leerlas todas: del elemento
+// dimension que Excel guarda al inicio del XML de la hoja o, si el archivo no lo calculó,
+// estimándolo por la proporción del XML que ocupan las primeras filas
+func (w *xlsxWorkbook) RowCount(sheet string) (int, bool) {
+	archive, err := zip.NewReader(w.source, w.size)
+	if err != nil {
+		return 0, false
+	}
+	part, ok := xlsxSheetPart(archive, sheet)
+	if !ok {
+		return 0, false
+	}
+
+	for _, file := range archive.File {
+		if file.Name == part {
+			return sheetRowCount(file)
+		}
+	}
+	return 0, false
+}
+
+// sheetRowCount lee el inicio del XML de una hoja hasta encontrar su dimensión o hasta
+// xlsxSampleSize bytes; en ese caso estima el total por el tamaño descomprimido de la hoja
+func sheetRowCount(part *zip.File) (int, bool) {
+	file, err := part.Open()
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	lastRow := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return max(lastRow-1, 0), true
+		}
+		if err != nil {
+			return 0, false
+		}
+		element, isStart := token.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+
+		switch element.Name.Local {
+		case "dimension":
+			if rows, ok := dimensionRows(xmlAttr(element, "ref")); ok {
+				return rows, true
+			}
+		case "row":
+			lastRow++
+			if row, err := strconv.Atoi(xmlAttr(element, "r")); err == nil {
+				lastRow = row
+			}
+			if offset := decoder.InputOffset(); offset >= xlsxSampleSize {
+				estimate := float64(lastRow) * float64(part.UncompressedSize64) / float64(offset)
+				return max(int(estimate)-1, 0), true
+			}
+		}
+	}
+}
+
+// xmlAttr obtiene el valor de un atributo de un elemento XML
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// xlsxSheetPart obtiene la ruta dentro del ZIP del XML de una hoja a partir de
+// workbook.xml y sus relaciones
+func xlsxSheetPart(archive *zip.Reader, sheet string) (string, bool) {
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var relationships struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if !decodeZipXML(archive, xlsxWorkbookPart, &workbook) || !decodeZipXML(archive, xlsxWorkbookRels, &relationships) {
+		return "", false
+	}
+
+	for _, entry := range workbook.Sheets {
+		if entry.Name != sheet {
+			continue
+		}
+		for _, relationship := range relationships.Relationships {
+			if relationship.ID != entry.RID {
+				continue
+			}
+			if strings.HasPrefix(relationship.Target, "/") {
+				return strings.TrimPrefix(relationship.Target, "/"), true
+			}
+			return path.Join(path.Dir(xlsxWorkbookPart), relationship.Target), true
+		}
+	}
+	return "", false
+}
+
+// decodeZipXML decodifica un archivo XML del ZIP
+func decodeZipXML(archive *zip.Reader, name string, target interface{}) bool {
+	file, err := archive.Open(name)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	return xml.NewDecoder(file).Decode(target) == nil
+}
+
+// dimensionRows obtiene el número de filas después del header de un rango como A1:D500;
+// un rango de una sola celda no se considera porque algunos programas lo escriben sin
+// calcular la dimensión real
+func dimensionRows(ref string) (int, bool) {
+	_, last, isRange := strings.Cut(ref, ":")
+	if !isRange {
+		return 0, false
+	}
+	row, err := strconv.Atoi(strings.TrimLeft(last, "ABCDEFGHIJKLMNOPQRSTUVWXYZ$"))
+	if err != nil || row < 1 {
+		return 0, false
+	}
+	return row - 1, true
+}
+
+// xlsxRows adapta el iterador de filas de excelize
+type xlsxRows struct {
+	rows *excelize.Rows
+}
+
+func (r *xlsxRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *xlsxRows) Columns() ([]string, error) {
+	if err := r.rows.Error(); err != nil {
+		return nil, err
+	}
+	return r.rows.Columns()
+}
+
+func (r *xlsxRows) Close() error {
+	return r.rows.Close()
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,15 +14,36 @@ import (
 
 type InMemoryContactRepository struct {
 	contacts map[int]*entities.Contact
-	nextID   int
-	mutex    sync.RWMutex
+	// keyIndex guarda los IDs de los contactos de cada dataset por clave cliente y
+	// indexedKeys la clave con que quedó indexado cada contacto; se actualizan con el
+	// mismo mutex que contacts
+	keyIndex        map[string]map[string][]int
+	indexedKeys     map[int]datasetKey
+	nextID          int
+	uniqueClientKey bool
+	mutex           sync.RWMutex
+}
+
+// datasetKey identifica una clave cliente dentro de un dataset
+type datasetKey struct {
+	datasetID string
+	clientKey string
+}
+
+// ContactRepositoryOptions configura las restricciones del repositorio de contactos
+type ContactRepositoryOptions struct {
+	// UniqueClientKey impide que dos contactos del mismo dataset compartan clave cliente
+	UniqueClientKey bool
 }
 
 // NewInMemoryContactRepository crea una nueva instancia del repositorio en memoria
-func NewInMemoryContactRepository() repositories.ContactRepository {
+func NewInMemoryContactRepository(options ContactRepositoryOptions) repositories.ContactRepository {
 	return &InMemoryContactRepository{
-		contacts: make(map[int]*entities.Contact),
-		nextID:   1,
+		contacts:        make(map[int]*entities.Contact),
+		keyIndex:        make(map[string]map[string][]int),
+		indexedKeys:     make(map[int]datasetKey),
+		nextID:          1,
+		uniqueClientKey: options.UniqueClientKey,
 	}
 }
 
@@ -29,6 +52,10 @@ func (r *InMemoryContactRepository) Save(contact *entities.Contact) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if err := r.checkUniqueKeys([]*entities.Contact{contact}); err != nil {
+		return err
+	}
+
 	if contact.ID == 0 {
 		contact.ID = r.nextID
 		r.nextID++
@@ -36,19 +63,23 @@ func (r *InMemoryContactRepository) Save(contact *entities.Contact) error {
 	}
 	contact.UpdatedAt = time.Now()
 	
-	r.contacts[contact.ID] = contact
+	r.store(contact)
 	return nil
 }
 
-// FindAll obtiene todos los contactos
-func (r *InMemoryContactRepository) FindAll() ([]*entities.Contact, error) {
+// FindAll obtiene todos los contactos de un dataset ordenados por ID
+func (r *InMemoryContactRepository) FindAll(datasetID string) ([]*entities.Contact, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	contacts := make([]*entities.Contact, 0, len(r.contacts))
+	contacts := make([]*entities.Contact, 0)
 	for _, contact := range r.contacts {
-		contacts = append(contacts, contact)
+		if contact.DatasetID == datasetID {
+			contacts = append(contacts, contact)
+		}
 	}
+
+	sortByID(contacts)
 	return contacts, nil
 }
 
@@ -73,8 +104,12 @@ func (r *InMemoryContactRepository) Update(contact *entities.Contact) error {
 		return errors.New("contacto no encontrado")
 	}
 
+	if err := r.checkUniqueKeys([]*entities.Contact{contact}); err != nil {
+		return err
+	}
+
 	contact.UpdatedAt = time.Now()
-	r.contacts[contact.ID] = contact
+	r.store(contact)
 	return nil
 }
 
@@ -87,12 +122,12 @@ func (r *InMemoryContactRepository) Delete(id int) error {
 		return errors.New("contacto no encontrado")
 	}
 
-	delete(r.contacts, id)
+	r.remove(id)
 	return nil
 }
 
-// Search busca contactos por campo y valor
-func (r *InMemoryContactRepository) Search(field, value string) ([]*entities.Contact, error) {
+// Search busca contactos de un dataset por campo y valor
+func (r *InMemoryContactRepository) Search(datasetID, field, value string) ([]*entities.Contact, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
@@ -100,6 +135,10 @@ func (r *InMemoryContactRepository) Search(field, value string) ([]*entities.Con
 	searchValue := strings.ToLower(value)
 
 	for _, contact := range r.contacts {
+		if contact.DatasetID != datasetID {
+			continue
+		}
+
 		var fieldValue string
 		switch field {
 		case "client_key":
@@ -119,14 +158,19 @@ func (r *InMemoryContactRepository) Search(field, value string) ([]*entities.Con
 		}
 	}
 
+	sortByID(results)
 	return results, nil
 }
 
-// SaveBatch guarda múltiples contactos
+// SaveBatch guarda múltiples contactos; si alguno viola la restricción de clave única no se guarda ninguno
 func (r *InMemoryContactRepository) SaveBatch(contacts []*entities.Contact) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if err := r.checkUniqueKeys(contacts); err != nil {
+		return err
+	}
+
 	for _, contact := range contacts {
 		if contact.ID == 0 {
 			contact.ID = r.nextID
@@ -134,8 +178,312 @@ func (r *InMemoryContactRepository) SaveBatch(contacts []*entities.Contact) erro
 			contact.CreatedAt = time.Now()
 		}
 		contact.UpdatedAt = time.Now()
-		r.contacts[contact.ID] = contact
+		r.store(contact)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// UpdateBatch actualiza varios contactos existentes en una sola operación: si alguno
+// no existe o viola la restricción de clave única no se actualiza ninguno
+func (r *InMemoryContactRepository) UpdateBatch(contacts []*entities.Contact) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, contact := range contacts {
+		if _, exists := r.contacts[contact.ID]; !exists {
+			return errors.New("contacto no encontrado")
+		}
+	}
+
+	if err := r.checkUniqueKeys(contacts); err != nil {
+		return err
+	}
+
+	for _, contact := range contacts {
+		contact.UpdatedAt = time.Now()
+		r.store(contact)
+	}
+
+	return nil
+}
+
+// Count cuenta los contactos de un dataset
+func (r *InMemoryContactRepository) Count(datasetID string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, contact := range r.contacts {
+		if contact.DatasetID == datasetID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteByDataset elimina todos los contactos de un dataset
+func (r *InMemoryContactRepository) DeleteByDataset(datasetID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, contact := range r.contacts {
+		if contact.DatasetID == datasetID {
+			r.remove(id)
+		}
+	}
+	return nil
+}
+
+// DeleteBatch elimina varios contactos por ID; los que no existen se ignoran
+func (r *InMemoryContactRepository) DeleteBatch(ids []int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, id := range ids {
+		r.remove(id)
+	}
+	return nil
+}
+
+// MoveToDataset pasa todos los contactos de un dataset a otro en una sola operación: si
+// alguno viola la restricción de clave única en el dataset destino no se mueve ninguno
+func (r *InMemoryContactRepository) MoveToDataset(fromDatasetID, toDatasetID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var moved []*entities.Contact
+	for _, contact := range r.contacts {
+		if contact.DatasetID == fromDatasetID {
+			copied := *contact
+			copied.DatasetID = toDatasetID
+			moved = append(moved, &copied)
+		}
+	}
+
+	if err := r.checkUniqueKeys(moved); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, contact := range moved {
+		contact.UpdatedAt = now
+		r.store(contact)
+	}
+	return nil
+}
+
+// FindPage obtiene hasta limit contactos de un dataset con ID mayor que afterID, ordenados
+// por ID. Recorrer un dataset página por página cuesta lo mismo que recorrer los IDs una vez.
+func (r *InMemoryContactRepository) FindPage(datasetID string, afterID, limit int) ([]*entities.Contact, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	contacts := make([]*entities.Contact, 0, limit)
+	for id := afterID + 1; id < r.nextID && len(contacts) < limit; id++ {
+		if contact, exists := r.contacts[id]; exists && contact.DatasetID == datasetID {
+			contacts = append(contacts, contact)
+		}
+	}
+	return contacts, nil
+}
+
+// FindByClientKeys obtiene los contactos de un dataset con alguna de las claves cliente
+// indicadas, ordenados por ID
+func (r *InMemoryContactRepository) FindByClientKeys(datasetID string, clientKeys []string) ([]*entities.Contact, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := r.keyIndex[datasetID]
+	seen := make(map[string]bool, len(clientKeys))
+	var contacts []*entities.Contact
+	for _, clientKey := range clientKeys {
+		if seen[clientKey] {
+			continue
+		}
+		seen[clientKey] = true
+		for _, id := range keys[clientKey] {
+			contacts = append(contacts, r.contacts[id])
+		}
+	}
+
+	sortByID(contacts)
+	return contacts, nil
+}
+
+// MergeByClientKey pasa los contactos de un dataset a otro en una sola operación usando la
+// clave cliente como llave natural: un contacto con la clave de un contacto del destino lo
+// reemplaza conservando su ID y su fecha de creación, y los demás se agregan. Los contactos
+// del destino cuya clave no aparece en el origen ni en absent.SeenDatasetID se marcan o se
+// eliminan según absent. Si una clave corresponde a varios contactos del destino o se viola
+// la restricción de clave única no se cambia nada. Retorna cuántos contactos se marcaron o
+// se eliminaron.
+func (r *InMemoryContactRepository) MergeByClientKey(fromDatasetID, toDatasetID string, absent repositories.AbsentContacts) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	targetKeys := r.keyIndex[toDatasetID]
+	var written []*entities.Contact
+	var replacedFrom []int
+	replaced := make(map[int]bool)
+	for _, contact := range r.contacts {
+		if contact.DatasetID != fromDatasetID {
+			continue
+		}
+
+		merged := *contact
+		merged.DatasetID = toDatasetID
+		merged.UpdatedAt = now
+		if owners := targetKeys[contact.ClientKey]; contact.ClientKey != "" && len(owners) > 0 {
+			if len(owners) > 1 || replaced[owners[0]] {
+				return 0, fmt.Errorf("la clave cliente %s corresponde a varios contactos", contact.ClientKey)
+			}
+			merged.ID = owners[0]
+			merged.CreatedAt = r.contacts[owners[0]].CreatedAt
+			replaced[merged.ID] = true
+			replacedFrom = append(replacedFrom, contact.ID)
+		}
+		written = append(written, &merged)
+	}
+
+	sourceKeys, seenKeys := r.keyIndex[fromDatasetID], r.keyIndex[absent.SeenDatasetID]
+	var removed []int
+	marked := 0
+	for _, contact := range r.contacts {
+		if contact.DatasetID != toDatasetID || replaced[contact.ID] {
+			continue
+		}
+		if key := contact.ClientKey; key != "" && (len(sourceKeys[key]) > 0 || len(seenKeys[key]) > 0) {
+			continue
+		}
+
+		switch {
+		case absent.Remove:
+			removed = append(removed, contact.ID)
+		case absent.Status != "" && contact.Status != absent.Status:
+			absentContact := *contact
+			absentContact.Status = absent.Status
+			absentContact.UpdatedAt = now
+			written = append(written, &absentContact)
+			marked++
+		}
+	}
+
+	if err := r.checkUniqueKeys(written); err != nil {
+		return 0, err
+	}
+
+	for _, id := range replacedFrom {
+		r.remove(id)
+	}
+	for _, id := range removed {
+		r.remove(id)
+	}
+	for _, contact := range written {
+		r.store(contact)
+	}
+	return len(removed) + marked, nil
+}
+
+// store guarda un contacto y actualiza el índice de claves cliente
+func (r *InMemoryContactRepository) store(contact *entities.Contact) {
+	r.unindex(contact.ID)
+	r.contacts[contact.ID] = contact
+	if contact.ClientKey == "" {
+		return
+	}
+
+	keys, exists := r.keyIndex[contact.DatasetID]
+	if !exists {
+		keys = make(map[string][]int)
+		r.keyIndex[contact.DatasetID] = keys
+	}
+	keys[contact.ClientKey] = append(keys[contact.ClientKey], contact.ID)
+	r.indexedKeys[contact.ID] = datasetKey{contact.DatasetID, contact.ClientKey}
+}
+
+// remove elimina un contacto y su entrada del índice de claves cliente
+func (r *InMemoryContactRepository) remove(id int) {
+	r.unindex(id)
+	delete(r.contacts, id)
+}
+
+// unindex quita un contacto del índice de claves cliente. Usa la clave con que se indexó
+// porque el contacto guardado pudo modificarse fuera del repositorio.
+func (r *InMemoryContactRepository) unindex(id int) {
+	key, exists := r.indexedKeys[id]
+	if !exists {
+		return
+	}
+	delete(r.indexedKeys, id)
+
+	keys := r.keyIndex[key.datasetID]
+	ids := keys[key.clientKey]
+	for i, indexed := range ids {
+		if indexed == id {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+	switch {
+	case len(ids) > 0:
+		keys[key.clientKey] = ids
+	case len(keys) > 1:
+		delete(keys, key.clientKey)
+	default:
+		delete(r.keyIndex, key.datasetID)
+	}
+}
+
+// checkUniqueKeys verifica que las claves cliente de los contactos no estén en uso por
+// otros contactos del mismo dataset ni repetidas entre ellos. Las claves vacías y los
+// datasets provisionales se ignoran. Solo revisa en el índice las claves de los contactos
+// a escribir.
+func (r *InMemoryContactRepository) checkUniqueKeys(contacts []*entities.Contact) error {
+	if !r.uniqueClientKey {
+		return nil
+	}
+
+	writing := make(map[int]bool, len(contacts))
+	for _, contact := range contacts {
+		if contact.ID != 0 {
+			writing[contact.ID] = true
+		}
+	}
+
+	batchOwners := make(map[datasetKey]int)
+	for _, contact := range contacts {
+		if contact.ClientKey == "" || strings.HasPrefix(contact.DatasetID, repositories.StagingDatasetPrefix) {
+			continue
+		}
+
+		key := datasetKey{contact.DatasetID, contact.ClientKey}
+		for _, ownerID := range r.keyIndex[key.datasetID][key.clientKey] {
+			if !writing[ownerID] {
+				return &repositories.DuplicateKeyError{
+					DatasetID:  contact.DatasetID,
+					ClientKey:  contact.ClientKey,
+					ExistingID: ownerID,
+				}
+			}
+		}
+		if ownerID, exists := batchOwners[key]; exists {
+			return &repositories.DuplicateKeyError{
+				DatasetID:  contact.DatasetID,
+				ClientKey:  contact.ClientKey,
+				ExistingID: ownerID,
+			}
+		}
+		batchOwners[key] = contact.ID
+	}
+
+	return nil
+}
+
+// sortByID ordena los contactos por ID para que la paginación sea estable
+func sortByID(contacts []*entities.Contact) {
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].ID < contacts[j].ID
+	})
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+func TestUniqueClientKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(repo repositories.ContactRepository) error
+		// duplicate indica si la escritura debe fallar con DuplicateKeyError
+		duplicate bool
+	}{
+		{
+			name: "clave nueva",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.Save(&entities.Contact{DatasetID: "a", ClientKey: "3"})
+			},
+		},
+		{
+			name: "clave en uso en el mismo dataset",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.Save(&entities.Contact{DatasetID: "a", ClientKey: "1"})
+			},
+			duplicate: true,
+		},
+		{
+			name: "clave en uso en otro dataset",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.Save(&entities.Contact{DatasetID: "b", ClientKey: "1"})
+			},
+		},
+		{
+			name: "claves vacías",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.SaveBatch([]*entities.Contact{{DatasetID: "a"}, {DatasetID: "a"}})
+			},
+		},
+		{
+			name: "clave repetida dentro del lote",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.SaveBatch([]*entities.Contact{{DatasetID: "a", ClientKey: "5"}, {DatasetID: "a", ClientKey: "5"}})
+			},
+			duplicate: true,
+		},
+		{
+			name: "dataset provisional",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.SaveBatch([]*entities.Contact{
+					{DatasetID: repositories.StagingDatasetPrefix + "x", ClientKey: "5"},
+					{DatasetID: repositories.StagingDatasetPrefix + "x", ClientKey: "5"},
+				})
+			},
+		},
+		{
+			name: "actualizar un contacto con su propia clave",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.Update(&entities.Contact{ID: 1, DatasetID: "a", ClientKey: "1", Name: "Ana"})
+			},
+		},
+		{
+			name: "actualizar un contacto con la clave de otro",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.Update(&entities.Contact{ID: 1, DatasetID: "a", ClientKey: "2"})
+			},
+			duplicate: true,
+		},
+		{
+			name: "intercambiar claves en un lote",
+			write: func(repo repositories.ContactRepository) error {
+				return repo.UpdateBatch([]*entities.Contact{{ID: 1, DatasetID: "a", ClientKey: "2"}, {ID: 2, DatasetID: "a", ClientKey: "1"}})
+			},
+		},
+		{
+			name: "mover contactos a un dataset con las mismas claves",
+			write: func(repo repositories.ContactRepository) error {
+				if err := repo.Save(&entities.Contact{DatasetID: "b", ClientKey: "1"}); err != nil {
+					return err
+				}
+				return repo.MoveToDataset("b", "a")
+			},
+			duplicate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryContactRepository(ContactRepositoryOptions{UniqueClientKey: true})
+			if err := repo.SaveBatch([]*entities.Contact{{DatasetID: "a", ClientKey: "1"}, {DatasetID: "a", ClientKey: "2"}}); err != nil {
+				t.Fatalf("SaveBatch() error = %v", err)
+			}
+
+			err := tt.write(repo)
+			var duplicateErr *repositories.DuplicateKeyError
+			if got := errors.As(err, &duplicateErr); got != tt.duplicate || (!tt.duplicate && err != nil) {
+				t.Fatalf("error = %v, se esperaba DuplicateKeyError: %v", err, tt.duplicate)
+			}
+			if tt.duplicate {
+				if count, _ := repo.Count("a"); count != 2 {
+					t.Errorf("una escritura rechazada dejó %d contactos en el dataset", count)
+				}
+			}
+		})
+	}
+}
+
+func TestClientKeyIndex(t *testing.T) {
+	repo := NewInMemoryContactRepository(ContactRepositoryOptions{})
+	contacts := []*entities.Contact{
+		{DatasetID: "a", ClientKey: "1"},
+		{DatasetID: "a", ClientKey: "1"},
+		{DatasetID: "a", ClientKey: "2"},
+		{DatasetID: "b", ClientKey: "1"},
+	}
+	if err := repo.SaveBatch(contacts); err != nil {
+		t.Fatalf("SaveBatch() error = %v", err)
+	}
+
+	// Cambiar la clave de un contacto guardado debe moverlo en el índice
+	changed := *contacts[1]
+	changed.ClientKey = "3"
+	if err := repo.Update(&changed); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := repo.Delete(contacts[2].ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dataset string
+		keys    []string
+		ids     []int
+	}{
+		{name: "clave de un contacto", dataset: "a", keys: []string{"1"}, ids: []int{contacts[0].ID}},
+		{name: "clave cambiada", dataset: "a", keys: []string{"3", "3"}, ids: []int{contacts[1].ID}},
+		{name: "contacto eliminado", dataset: "a", keys: []string{"2"}},
+		{name: "varias claves en orden de ID", dataset: "a", keys: []string{"3", "1"}, ids: []int{contacts[0].ID, contacts[1].ID}},
+		{name: "otro dataset", dataset: "b", keys: []string{"1", "3"}, ids: []int{contacts[3].ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.FindByClientKeys(tt.dataset, tt.keys)
+			if err != nil {
+				t.Fatalf("FindByClientKeys() error = %v", err)
+			}
+			if len(found) != len(tt.ids) {
+				t.Fatalf("FindByClientKeys() retornó %d contactos, se esperaban %d", len(found), len(tt.ids))
+			}
+			for i, contact := range found {
+				if contact.ID != tt.ids[i] {
+					t.Errorf("contacto %d = ID %d, se esperaba %d", i, contact.ID, tt.ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeByClientKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		absent repositories.AbsentContacts
+		// changed es el número de contactos ausentes marcados o eliminados
+		changed int
+		// names es el nombre y estado de cada contacto del destino por clave cliente
+		names map[string]string
+	}{
+		{
+			name:  "reemplaza y agrega",
+			names: map[string]string{"1": "Ana Ruiz", "2": "Beto", "3": "Carla", "4": "Dora"},
+		},
+		{
+			name:    "marca los ausentes",
+			absent:  repositories.AbsentContacts{Status: entities.ContactStatusMissing},
+			changed: 2,
+			names:   map[string]string{"1": "Ana Ruiz", "2": "Beto" + entities.ContactStatusMissing, "3": "Carla" + entities.ContactStatusMissing, "4": "Dora"},
+		},
+		{
+			name:    "las claves vistas cuentan como presentes",
+			absent:  repositories.AbsentContacts{SeenDatasetID: "vistos", Remove: true},
+			changed: 1,
+			names:   map[string]string{"1": "Ana Ruiz", "2": "Beto", "4": "Dora"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryContactRepository(ContactRepositoryOptions{UniqueClientKey: true})
+			existing := []*entities.Contact{
+				{DatasetID: "destino", ClientKey: "1", Name: "Ana"},
+				{DatasetID: "destino", ClientKey: "2", Name: "Beto"},
+				{DatasetID: "destino", ClientKey: "3", Name: "Carla"},
+				{DatasetID: "cambios", ClientKey: "1", Name: "Ana Ruiz"},
+				{DatasetID: "cambios", ClientKey: "4", Name: "Dora"},
+				{DatasetID: "vistos", ClientKey: "2", Name: "Beto"},
+			}
+			if err := repo.SaveBatch(existing); err != nil {
+				t.Fatalf("SaveBatch() error = %v", err)
+			}
+
+			changed, err := repo.MergeByClientKey("cambios", "destino", tt.absent)
+			if err != nil {
+				t.Fatalf("MergeByClientKey() error = %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("MergeByClientKey() = %d, se esperaban %d ausentes", changed, tt.changed)
+			}
+
+			contacts, _ := repo.FindAll("destino")
+			names := make(map[string]string, len(contacts))
+			for _, contact := range contacts {
+				names[contact.ClientKey] = contact.Name + contact.Status
+				if contact.ClientKey == "1" && contact.ID != existing[0].ID {
+					t.Errorf("el contacto reemplazado cambió de ID: %d, se esperaba %d", contact.ID, existing[0].ID)
+				}
+			}
+			if len(names) != len(tt.names) {
+				t.Errorf("destino = %v, se esperaba %v", names, tt.names)
+			}
+			for key, name := range tt.names {
+				if names[key] != name {
+					t.Errorf("destino[%s] = %q, se esperaba %q", key, names[key], name)
+				}
+			}
+			if count, _ := repo.Count("cambios"); count != 0 {
+				t.Errorf("quedaron %d contactos en el dataset de origen", count)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type InMemoryDatasetRepository struct {
+	datasets map[string]*entities.Dataset
+	mutex    sync.RWMutex
+}
+
+// NewInMemoryDatasetRepository crea una nueva instancia del repositorio de datasets en memoria
+func NewInMemoryDatasetRepository() repositories.DatasetRepository {
+	return &InMemoryDatasetRepository{
+		datasets: make(map[string]*entities.Dataset),
+	}
+}
+
+// Save guarda una copia del dataset en memoria
+func (r *InMemoryDatasetRepository) Save(dataset *entities.Dataset) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.datasets[dataset.ID]; exists {
+		return errors.New("el dataset ya existe")
+	}
+
+	now := time.Now()
+	dataset.CreatedAt = now
+	dataset.UpdatedAt = now
+	r.datasets[dataset.ID] = copyDataset(dataset)
+	return nil
+}
+
+// FindAll obtiene copias de todos los datasets ordenadas por fecha de creación
+func (r *InMemoryDatasetRepository) FindAll() ([]*entities.Dataset, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	datasets := make([]*entities.Dataset, 0, len(r.datasets))
+	for _, dataset := range r.datasets {
+		datasets = append(datasets, copyDataset(dataset))
+	}
+
+	sort.Slice(datasets, func(i, j int) bool {
+		return datasets[i].CreatedAt.Before(datasets[j].CreatedAt)
+	})
+	return datasets, nil
+}
+
+// FindByID busca un dataset por ID y retorna una copia
+func (r *InMemoryDatasetRepository) FindByID(id string) (*entities.Dataset, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	dataset, exists := r.datasets[id]
+	if !exists {
+		return nil, errors.New("dataset no encontrado")
+	}
+	return copyDataset(dataset), nil
+}
+
+// Update reemplaza un dataset existente con una copia del indicado
+func (r *InMemoryDatasetRepository) Update(dataset *entities.Dataset) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.datasets[dataset.ID]; !exists {
+		return errors.New("dataset no encontrado")
+	}
+
+	dataset.UpdatedAt = time.Now()
+	r.datasets[dataset.ID] = copyDataset(dataset)
+	return nil
+}
+
+// Delete elimina un dataset por ID
+func (r *InMemoryDatasetRepository) Delete(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.datasets[id]; !exists {
+		return errors.New("dataset no encontrado")
+	}
+
+	delete(r.datasets, id)
+	return nil
+}
+
+// copyDataset copia un dataset para que quien lo obtiene no comparta el que guarda el repositorio
+func copyDataset(dataset *entities.Dataset) *entities.Dataset {
+	copied := *dataset
+	return &copied
+}
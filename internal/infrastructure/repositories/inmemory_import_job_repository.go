@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type InMemoryImportJobRepository struct {
+	jobs  map[string]*entities.ImportJob
+	mutex sync.RWMutex
+}
+
+// NewInMemoryImportJobRepository crea una nueva instancia del repositorio de trabajos de importación
+func NewInMemoryImportJobRepository() repositories.ImportJobRepository {
+	return &InMemoryImportJobRepository{
+		jobs: make(map[string]*entities.ImportJob),
+	}
+}
+
+// Save guarda una copia del trabajo y descarta los trabajos terminados que ya expiraron
+func (r *InMemoryImportJobRepository) Save(job *entities.ImportJob) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	for id, existing := range r.jobs {
+		if existing.ExpiresAt != nil && now.After(*existing.ExpiresAt) {
+			delete(r.jobs, id)
+		}
+	}
+
+	r.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// FindByID busca un trabajo por ID y retorna una copia
+func (r *InMemoryImportJobRepository) FindByID(id string) (*entities.ImportJob, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	job, exists := r.jobs[id]
+	if !exists || (job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt)) {
+		return nil, errors.New("trabajo de importación no encontrado")
+	}
+	return copyJob(job), nil
+}
+
+// FindAll obtiene copias de todos los trabajos vigentes, del más reciente al más antiguo
+func (r *InMemoryImportJobRepository) FindAll() ([]*entities.ImportJob, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	now := time.Now()
+	jobs := make([]*entities.ImportJob, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		if job.ExpiresAt == nil || !now.After(*job.ExpiresAt) {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs, nil
+}
+
+// copyJob copia un trabajo para que el repositorio no comparta el que se sigue actualizando
+func copyJob(job *entities.ImportJob) *entities.ImportJob {
+	copied := *job
+	copied.Errors = append([]string{}, job.Errors...)
+	return &copied
+}
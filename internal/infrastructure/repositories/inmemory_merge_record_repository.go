@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type InMemoryMergeRecordRepository struct {
+	records []*entities.MergeRecord
+	mutex   sync.RWMutex
+}
+
+// NewInMemoryMergeRecordRepository crea una nueva instancia del repositorio de fusiones en memoria
+func NewInMemoryMergeRecordRepository() repositories.MergeRecordRepository {
+	return &InMemoryMergeRecordRepository{}
+}
+
+// Save guarda un registro de fusión
+func (r *InMemoryMergeRecordRepository) Save(record *entities.MergeRecord) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	record.CreatedAt = time.Now()
+	r.records = append(r.records, record)
+	return nil
+}
+
+// FindAll obtiene los registros de fusión de un dataset en orden cronológico
+func (r *InMemoryMergeRecordRepository) FindAll(datasetID string) ([]*entities.MergeRecord, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	records := make([]*entities.MergeRecord, 0)
+	for _, record := range r.records {
+		if record.DatasetID == datasetID {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// DeleteByDataset elimina los registros de fusión de un dataset
+func (r *InMemoryMergeRecordRepository) DeleteByDataset(datasetID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kept := r.records[:0]
+	for _, record := range r.records {
+		if record.DatasetID != datasetID {
+			kept = append(kept, record)
+		}
+	}
+	r.records = kept
+	return nil
+}
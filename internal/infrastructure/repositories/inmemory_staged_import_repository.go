@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"analizador-backend/internal/domain/entities"
+	"analizador-backend/internal/domain/repositories"
+)
+
+type InMemoryStagedImportRepository struct {
+	staged map[string]*entities.StagedImport
+	mutex  sync.RWMutex
+}
+
+// NewInMemoryStagedImportRepository crea una nueva instancia del repositorio de cargas en vista previa
+func NewInMemoryStagedImportRepository() repositories.StagedImportRepository {
+	return &InMemoryStagedImportRepository{
+		staged: make(map[string]*entities.StagedImport),
+	}
+}
+
+// Save guarda una carga en vista previa
+func (r *InMemoryStagedImportRepository) Save(staged *entities.StagedImport) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.staged[staged.Token] = staged
+	return nil
+}
+
+// FindByToken busca una carga en vista previa vigente por su token
+func (r *InMemoryStagedImportRepository) FindByToken(token string) (*entities.StagedImport, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	staged, exists := r.staged[token]
+	if !exists || time.Now().After(staged.ExpiresAt) {
+		return nil, errors.New("carga en vista previa no encontrada o expirada")
+	}
+	return staged, nil
+}
+
+// Delete elimina una carga en vista previa por su token
+func (r *InMemoryStagedImportRepository) Delete(token string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.staged[token]; !exists {
+		return errors.New("carga en vista previa no encontrada o expirada")
+	}
+
+	delete(r.staged, token)
+	return nil
+}
+
+// DeleteExpired elimina las cargas en vista previa que expiraron antes de now y las retorna
+func (r *InMemoryStagedImportRepository) DeleteExpired(now time.Time) ([]*entities.StagedImport, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var expired []*entities.StagedImport
+	for token, staged := range r.staged {
+		if now.After(staged.ExpiresAt) {
+			expired = append(expired, staged)
+			delete(r.staged, token)
+		}
+	}
+	return expired, nil
+}
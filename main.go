@@ -3,35 +3,102 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"analizador-backend/internal/application/services"
+	"analizador-backend/internal/infrastructure/config"
 	"analizador-backend/internal/infrastructure/handlers"
+	"analizador-backend/internal/infrastructure/readers"
 	"analizador-backend/internal/infrastructure/repositories"
 )
 
 func main() {
 	// Inicializar dependencias
-	contactRepo := repositories.NewInMemoryContactRepository()
+	contactRepo := repositories.NewInMemoryContactRepository(repositories.ContactRepositoryOptions{
+		UniqueClientKey: os.Getenv("UNIQUE_CLIENT_KEY") == "true",
+	})
+	datasetRepo := repositories.NewInMemoryDatasetRepository()
+	stagedImportRepo := repositories.NewInMemoryStagedImportRepository()
+	importJobRepo := repositories.NewInMemoryImportJobRepository()
+	mergeRecordRepo := repositories.NewInMemoryMergeRecordRepository()
 	validatorService := services.NewValidatorService()
+	if path := os.Getenv("VALIDATION_PROFILES"); path != "" {
+		profiles, err := config.LoadValidationProfiles(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := validatorService.LoadProfiles(profiles); err != nil {
+			log.Fatal(err)
+		}
+	}
 	contactService := services.NewContactService(contactRepo, validatorService)
-	contactHandler := handlers.NewContactHandler(contactService)
+	if workers := os.Getenv("VALIDATION_WORKERS"); workers != "" {
+		n, err := strconv.Atoi(workers)
+		if err != nil {
+			log.Fatalf("VALIDATION_WORKERS inválido: %s", workers)
+		}
+		contactService.SetValidationWorkers(n)
+	}
+	datasetService := services.NewDatasetService(datasetRepo, contactService, mergeRecordRepo, validatorService)
+	importService := services.NewImportService(contactService, datasetService, stagedImportRepo)
+	importJobService := services.NewImportJobService(importService, importJobRepo)
+	if workers := os.Getenv("IMPORT_JOB_WORKERS"); workers != "" {
+		n, err := strconv.Atoi(workers)
+		if err != nil {
+			log.Fatalf("IMPORT_JOB_WORKERS inválido: %s", workers)
+		}
+		importJobService.SetImportJobWorkers(n)
+	}
+	duplicateService := services.NewDuplicateService(contactService)
+	mergeService := services.NewMergeService(contactService, validatorService, mergeRecordRepo)
+	fixService := services.NewFixService(contactService, validatorService)
+	workbookOpener := readers.NewReaderRegistry(
+		readers.NewODSReader(),
+		readers.NewXLSXReader(),
+		readers.NewXLSReader(),
+		readers.NewDelimitedReader(),
+	)
+	contactHandler := handlers.NewContactHandler(contactService, datasetService, importService, importJobService, workbookOpener)
+	datasetHandler := handlers.NewDatasetHandler(datasetService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService, mergeService, datasetService)
+	fixHandler := handlers.NewFixHandler(fixService, datasetService)
+	validationHandler := handlers.NewValidationHandler(validatorService)
+	importJobHandler := handlers.NewImportJobHandler(importJobService)
 
 	// Configurar router
 	router := gin.Default()
 	
 	// Configurar CORS
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:5173"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	router.Use(cors.New(config))
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = []string{"http://localhost:5173"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	router.Use(cors.New(corsConfig))
 
 	// Rutas
 	api := router.Group("/api/v1")
 	{
 		api.POST("/contacts/upload", contactHandler.UploadExcel)
+		api.POST("/contacts/upload/sheets", contactHandler.ListSheets)
+		api.POST("/contacts/upload/preview", contactHandler.PreviewUpload)
+		api.POST("/contacts/imports/:token/confirm", contactHandler.ConfirmImport)
+		api.DELETE("/contacts/imports/:token", contactHandler.DiscardImport)
+		api.GET("/contacts/jobs", importJobHandler.GetJobs)
+		api.GET("/contacts/jobs/:job_id", importJobHandler.GetJob)
+		api.POST("/contacts/jobs/:job_id/cancel", importJobHandler.CancelJob)
+
+		api.GET("/datasets", datasetHandler.GetDatasets)
+		api.GET("/datasets/:dataset_id", datasetHandler.GetDataset)
+		api.DELETE("/datasets/:dataset_id", datasetHandler.DeleteDataset)
+		api.PUT("/datasets/:dataset_id/profile", datasetHandler.UpdateDatasetProfile)
+
+		api.GET("/validation/rules", validationHandler.GetRules)
+		api.GET("/validation/profiles", validationHandler.GetProfiles)
+
+		// Rutas anteriores a los datasets: trabajan sobre el dataset cargado más recientemente
 		api.GET("/contacts", contactHandler.GetContacts)
 		api.GET("/contacts/search", contactHandler.SearchContacts)
 		api.PUT("/contacts/:id", contactHandler.UpdateContact)
@@ -39,6 +106,25 @@ func main() {
 		api.GET("/contacts/download", contactHandler.DownloadExcel)
 	}
 
+	// Rutas de contactos por dataset
+	dataset := api.Group("/datasets/:dataset_id")
+	{
+		dataset.POST("/contacts/upload", contactHandler.UploadExcel)
+		dataset.POST("/contacts/upload/preview", contactHandler.PreviewUpload)
+		dataset.GET("/contacts", contactHandler.GetContacts)
+		dataset.GET("/contacts/search", contactHandler.SearchContacts)
+		dataset.PUT("/contacts/:id", contactHandler.UpdateContact)
+		dataset.DELETE("/contacts/:id", contactHandler.DeleteContact)
+		dataset.GET("/contacts/validate", contactHandler.ValidateContacts)
+		dataset.GET("/contacts/validate/summary", contactHandler.GetValidationSummary)
+		dataset.GET("/contacts/download", contactHandler.DownloadExcel)
+		dataset.GET("/contacts/duplicates", duplicateHandler.GetDuplicates)
+		dataset.POST("/contacts/merge", duplicateHandler.MergeContacts)
+		dataset.GET("/contacts/merges", duplicateHandler.GetMerges)
+		dataset.GET("/contacts/fixes", fixHandler.GetFixes)
+		dataset.POST("/contacts/fixes/apply", fixHandler.ApplyFixes)
+	}
+
 	log.Println("Servidor iniciado en puerto 8080")
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
\ No newline at end of file